@@ -0,0 +1,63 @@
+#!/bin/sh
+# Runs the action with a flag for each input that is set. Inputs that aren't set are
+# skipped, so the flags fall back to the values in the config file, if any, and then to
+# their defaults.
+set -e
+
+set -- --github-actions
+for pair in \
+  project-dir:PROJECTDIR \
+  merge-projects:MERGEPROJECTS \
+  outdated-locales:OUTDATEDLOCALES \
+  output-format:OUTPUTFORMAT \
+  output-dir:OUTPUTDIR \
+  output-file:OUTPUTFILE \
+  write-stubs:WRITESTUBS \
+  stub-value:STUBVALUE \
+  stub-comment:STUBCOMMENT \
+  xliff-version:XLIFFVERSION \
+  markdown-title:MARKDOWNTITLE \
+  source-base-url:SOURCEBASEURL \
+  locale-names:LOCALENAMES \
+  report:REPORT \
+  group-by:GROUPBY \
+  locale-aliases:LOCALEALIASES \
+  default-locale:DEFAULTLOCALE \
+  check-locale-config:CHECKLOCALECONFIG \
+  check-locale-support:CHECKLOCALESUPPORT \
+  check-stale:CHECKSTALE \
+  check-placeholders:CHECKPLACEHOLDERS \
+  respect-tools-ignore:RESPECTTOOLSIGNORE \
+  check-identical:CHECKIDENTICAL \
+  ignore-identical:IGNOREIDENTICAL \
+  fail-on-missing:FAILONMISSING \
+  fail-on-locale-config:FAILONLOCALECONFIG \
+  fail-on-placeholder-mismatch:FAILONPLACEHOLDERMISMATCH \
+  min-coverage-percent:MINCOVERAGEPERCENT \
+  min-locale-coverage-percent:MINLOCALECOVERAGEPERCENT \
+  required-locales:REQUIREDLOCALES \
+  baseline:BASELINE \
+  write-baseline:WRITEBASELINE \
+  prune-baseline:PRUNEBASELINE \
+  include-path:INCLUDEPATHS \
+  exclude-path:EXCLUDEPATHS \
+  ignore-strings:IGNORESTRINGS \
+  list-ignored:LISTIGNORED \
+  locales:LOCALES \
+  gradle-locale-filters:GRADLELOCALEFILTERS \
+  since-ref:SINCEREF \
+  github-step-summary:GITHUBSTEPSUMMARY \
+  github-comment:GITHUBCOMMENT \
+  github-issue:GITHUBISSUE \
+  github-issue-threshold:GITHUBISSUETHRESHOLD \
+  github-issue-title:GITHUBISSUETITLE \
+  jobs:JOBS \
+  config:CONFIG; do
+  flag=${pair%%:*}
+  eval "value=\${INPUT_${pair#*:}:-}"
+  if [ -n "$value" ]; then
+    set -- "$@" "--$flag=$value"
+  fi
+done
+
+exec /entrypoint "$@"
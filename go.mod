@@ -1,10 +1,17 @@
 module github.com/ashutoshgngwr/android-translations
 
-go 1.14
+go 1.17
 
 require (
-	github.com/mattn/go-runewidth v0.0.9 // indirect
+	github.com/fsnotify/fsnotify v1.4.9
 	github.com/olekukonko/tablewriter v0.0.4
 	github.com/pkg/errors v0.9.1
 	github.com/spf13/pflag v1.0.5
+	golang.org/x/text v0.13.0
+	gopkg.in/yaml.v2 v2.3.0
+)
+
+require (
+	github.com/mattn/go-runewidth v0.0.9 // indirect
+	golang.org/x/sys v0.7.0 // indirect
 )
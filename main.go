@@ -2,468 +2,772 @@ package main
 
 import (
 	"bytes"
-	"encoding/json"
-	"encoding/xml"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
-	"strconv"
 	"strings"
-	"text/template"
 	"time"
 
-	"github.com/olekukonko/tablewriter"
+	"github.com/ashutoshgngwr/android-translations/pkg/config"
+	"github.com/ashutoshgngwr/android-translations/pkg/github"
+	"github.com/ashutoshgngwr/android-translations/pkg/report"
+	"github.com/ashutoshgngwr/android-translations/pkg/resources"
+	"github.com/fsnotify/fsnotify"
 	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 )
 
-// doNotTranslateFileName as recognised by the Android Developer Tools
-// http://tools.android.com/recent/non-translatablestrings
-const doNotTranslateFileName = "donottranslate.xml"
+var (
+	projectDirs     []string // root directories or glob patterns of the Android Projects
+	mergeProjects   bool     // if true, merge the reports of multiple projects into a single table
+	outdatedLocales bool     // if true, also print potentially outdated locales
+	outputFormat    string   // output format, must be one of markdown, json, jsonl, html, sarif or xliff
+	outputDir       string   // directory to write the XLIFF files to
+	outputFile      string   // if not empty, also write the report to this file
+	writeStubs      bool     // if true, append stubs for the missing translations to the values files
+	stubValue       string   // values of the stubs, must be one of empty or default
+	stubComment     string   // if not empty, comment to add before each stub
+	xliffVersion    string   // version of the XLIFF files, must be one of 1.2 or 2.0
+	markdownTitle   string   // heading for markdown content
+	sourceBaseURL   string   // URL of the project directory to link the strings in the Markdown and HTML reports
+	localeNames     bool     // if true, include the English names of the locales with their coverage
+	githubActions   bool     // if true, also set the report as the action output
+	stepSummary     bool     // if true, append the markdown report to the job summary
+	githubComment   bool     // if true, post the markdown report as a sticky comment on the pull request
+	githubIssue     bool     // if true, open or update a tracking issue with the markdown report
+	issueThreshold  int      // minimum number of missing translations to open the tracking issue
+	issueTitle      string   // title of the tracking issue
+	localeAliases   []string // 'suffix=locale' pairs to map 'values-' suffixes to canonical locales
+	checkLocaleConf bool     // if true, validate declared locales in the app's localeConfig
+	checkSupport    bool     // if true, warn about locales that are never served to users
+	checkStale      bool     // if true, also find translations removed from the default locale
+	checkFormat     bool     // if true, validate placeholders of translations against default strings
+	checkIdentical  bool     // if true, also find translations identical to default strings
+	ignoreIdentical []string // regular expressions for the names or values of the strings that may be identical
+	toolsIgnore     bool     // if true, honor the 'tools:ignore' attributes of the resources
+	reportMode      string   // sections to include in the report, must be one of missing, coverage or all
+	groupBy         string   // attribute to group the strings by in the markdown report, must be one of module, directory or project
+	includePaths    []string // glob patterns of the values files to scan
+	excludePaths    []string // glob patterns of the paths to skip while finding values files
+	ignoreStrings   []string // regular expressions for the names of the strings to ignore
+	listIgnored     bool     // if true, list the ignored paths and strings in the report
+	sinceRef        string   // if not empty, only report the strings added or changed since this git ref
+	defaultLocale   string   // if not empty, locale of the default strings instead of the 'values' directories
+	locales         []string // if not empty, only report the translations of these locales
+	gradleLocales   bool     // if true, only report the locales declared using resConfigs or localeFilters
+	jobs            int      // number of values files to parse concurrently
+	baselineFile    string   // if not empty, path of the baseline file with the issues to suppress
+	writeBaseline   string   // if not empty, path to write the baseline file with the current issues to
+	pruneBaseline   bool     // if true, remove the issues that no longer apply from the baseline file
+	watch           bool     // if true, scan the projects again whenever their values files change
+	configFile      string   // path of the config file
+	thresholds      report.Thresholds
+)
 
-// xmlTranslatable is a generic struct that can be embedded in other structs
-// to parse values for 'translatable' attribute
-type xmlTranslatable struct {
-	Translatable string `xml:"translatable,attr"`
-}
+func init() {
+	pflag.CommandLine.SortFlags = false
+	pflag.StringVar(&configFile, "config", "", "Path of the config file. Defaults to '.android-translations.yml' in the project directory, if it exists")
+	pflag.StringSliceVar(&projectDirs, "project-dir", []string{"."}, "Android Project's root directory. Can be repeated or comma-separated to scan several projects, and may contain glob patterns, e.g. 'apps/*'")
+	pflag.BoolVar(&mergeProjects, "merge-projects", false, "If true, merge the reports of several projects into a single table with the project of each string. Otherwise, group the report by projects")
+	pflag.BoolVar(&outdatedLocales, "outdated-locales", true, "If true, find potentially outdated translations")
+	pflag.StringVar(&outputFormat, "output-format", "json", "Output format. Must be 'json', 'jsonl', 'markdown', 'html', 'sarif' or 'xliff'")
+	pflag.StringVar(&outputDir, "output-dir", ".", "Directory to write one XLIFF file per locale to. Only used with 'xliff' output format")
+	pflag.StringVar(&outputFile, "output-file", "", "If set, also write the report to this file, e.g. to publish the HTML report as a build artifact")
+	pflag.BoolVar(&writeStubs, "write-stubs", false, "If true, append stubs for the missing translations to the values files of their locales")
+	pflag.StringVar(&stubValue, "stub-value", "empty", "Values of the stubs. Must be 'empty' or 'default'")
+	pflag.StringVar(&stubComment, "stub-comment", "", "If set, add this comment before each stub, e.g. 'TODO: translate'")
+	pflag.StringVar(&xliffVersion, "xliff-version", report.XLIFFVersion12, "XLIFF version. Must be '1.2' or '2.0'")
+	pflag.StringVar(&markdownTitle, "markdown-title", "Android Translations", "Title for the Markdown content")
+	pflag.StringVar(&sourceBaseURL, "source-base-url", "", "URL of the project directory in a source browser to link the strings to their declarations in the Markdown and HTML reports, e.g. 'https://github.com/owner/repo/blob/main'")
+	pflag.BoolVar(&localeNames, "locale-names", false, "If true, include the English names of the locales, e.g. 'Chinese (China)' for 'zh-CN', with their coverage")
+	pflag.StringVar(&reportMode, "report", "missing", "Sections to include in the report. Must be 'missing', 'coverage' or 'all'")
+	pflag.StringVar(&groupBy, "group-by", "", "Group the strings in the Markdown report by 'module', 'directory' or 'project'")
+	pflag.BoolVar(&githubActions, "github-actions", false, "Indicates if the runtime is GitHub Actions")
+	pflag.BoolVar(&stepSummary, "github-step-summary", false, "If true, append the Markdown report to the job summary of GitHub Actions")
+	pflag.BoolVar(&githubComment, "github-comment", false, "If true, create or update a comment with the Markdown report on the pull request that triggered the workflow")
+	pflag.BoolVar(&githubIssue, "github-issue", false, "If true, create or update an issue with the Markdown report if the number of missing translations exceeds the threshold")
+	pflag.IntVar(&issueThreshold, "github-issue-threshold", 0, "Number of missing translations to exceed for creating the issue. The issue is closed otherwise")
+	pflag.StringVar(&issueTitle, "github-issue-title", "Missing Translations", "Title of the issue created with '--github-issue'")
+	pflag.StringSliceVar(&localeAliases, "locale-aliases", []string{}, "Comma-separated 'suffix=locale' pairs to map 'values-' suffixes to canonical locales")
+	pflag.BoolVar(&checkLocaleConf, "check-locale-config", true, "If true, validate locales declared in the app's localeConfig against translations")
+	pflag.BoolVar(&checkSupport, "check-locale-support", true, "If true, warn about locales that Android and Google Play never serve to users")
+	pflag.BoolVar(&checkStale, "check-stale", false, "If true, find stale translations whose strings no longer exist in the default locale")
+	pflag.BoolVar(&checkFormat, "check-placeholders", true, "If true, report translations whose placeholders don't match the default strings")
+	pflag.BoolVar(&checkIdentical, "check-identical", false, "If true, report translations identical to the default strings as suspicious")
+	pflag.StringSliceVar(&ignoreIdentical, "ignore-identical", []string{}, "Comma-separated names, values or regular expressions of the strings whose translations may be identical, e.g. brand names")
+	pflag.BoolVar(&toolsIgnore, "respect-tools-ignore", true, "If true, skip the checks suppressed using 'MissingTranslation', 'ExtraTranslation' or 'all' in 'tools:ignore' attributes of the resources")
+	pflag.BoolVar(&thresholds.FailOnMissing, "fail-on-missing", false, "If true, exit with a non-zero status if any translation is missing")
+	pflag.BoolVar(&thresholds.FailOnLocaleConfig, "fail-on-locale-config", false, "If true, exit with a non-zero status if the locales in the app's localeConfig don't match the translations")
+	pflag.BoolVar(&thresholds.FailOnPlaceholderMismatch, "fail-on-placeholder-mismatch", false, "If true, exit with a non-zero status if the placeholders of any translation don't match the default string")
+	pflag.Float64Var(&thresholds.MinCoveragePercent, "min-coverage-percent", 0, "Exit with a non-zero status if the overall coverage is below this percentage")
+	pflag.Float64Var(&thresholds.MinLocaleCoveragePercent, "min-locale-coverage-percent", 0, "Exit with a non-zero status if the coverage of any locale is below this percentage")
+	pflag.StringSliceVar(&thresholds.RequiredLocales, "required-locales", []string{}, "Comma-separated locales that must be completely translated")
+	pflag.StringVar(&baselineFile, "baseline", "", "If set, suppress the known issues listed in this baseline file and only report the new ones")
+	pflag.StringVar(&writeBaseline, "write-baseline", "", "If set, write the current issues to this baseline file, e.g. 'baseline.json'")
+	pflag.BoolVar(&pruneBaseline, "prune-baseline", false, "If true, remove the issues that no longer apply from the file set with '--baseline'")
+	pflag.StringSliceVar(&includePaths, "include-path", []string{}, "Comma-separated glob patterns of the values files, relative to the project directory, to scan")
+	pflag.StringSliceVar(&excludePaths, "exclude-path", []string{}, "Comma-separated glob patterns of the paths, relative to the project directory, to skip")
+	pflag.StringSliceVar(&ignoreStrings, "ignore-strings", []string{}, "Comma-separated names or regular expressions of the strings to ignore")
+	pflag.StringVar(&defaultLocale, "default-locale", "", "If set, use the strings of this locale, e.g. 'en', as the default strings instead of the strings in 'values' directories")
+	pflag.StringSliceVar(&locales, "locales", []string{}, "Comma-separated locales to restrict the report to, e.g. 'de,fr,pt-rBR'")
+	pflag.BoolVar(&gradleLocales, "gradle-locale-filters", false, "If true and '--locales' isn't set, restrict the report to the locales declared using 'resConfigs' or 'localeFilters' in Gradle build scripts")
+	pflag.StringVar(&sinceRef, "since-ref", "", "If set, only report the strings added or changed since the merge base of this git ref and HEAD, e.g. 'origin/main'")
+	pflag.BoolVar(&listIgnored, "list-ignored", false, "If true, list the ignored paths and strings in the report")
+	pflag.BoolVarP(&watch, "watch", "w", false, "If true, keep running and print the report again whenever the values files change")
+	pflag.IntVarP(&jobs, "jobs", "j", 0, "Number of values files to parse concurrently. Defaults to the number of CPUs")
+	pflag.Parse()
+	if githubActions {
+		setActionDefaults()
+	}
 
-// IsTranslatable returns false if the value of 'Translatable' attr was set
-// to 'false'. Returns true otherwise.
-func (res *xmlTranslatable) IsTranslatable() bool {
-	return !strings.EqualFold("false", res.Translatable)
-}
+	if err := loadConfig(); err != nil {
+		fatal(err)
+	}
 
-// xmlStringResources declares data structure for unmarshalling 'resources' tag in
-// Android values XML files.
-type xmlStringResources struct {
-	xml.Name     `xml:"resources"`
-	Strings      []xmlStringResource      `xml:"string"`
-	StringArrays []xmlStringArrayResource `xml:"string-array"`
-}
+	if outputFormat != "json" && outputFormat != "jsonl" && outputFormat != "markdown" &&
+		outputFormat != "html" && outputFormat != "sarif" && outputFormat != "xliff" {
+		fatal(fmt.Sprintf("unknow output format %s", outputFormat))
+	}
 
-// xmlStringResource declares data structure for unmarshalling 'string' tags in Android
-// values XML files.
-type xmlStringResource struct {
-	Name         string    `xml:"name,attr"`
-	Value        string    `xml:",chardata"`
-	LastModified time.Time `xml:"-"`
-	xmlTranslatable
-}
+	if xliffVersion != report.XLIFFVersion12 && xliffVersion != report.XLIFFVersion20 {
+		fatal(fmt.Sprintf("unknown XLIFF version %s", xliffVersion))
+	}
 
-type xmlStringArrayResource struct {
-	Name string `xml:"name,attr"`
-	// since items have only the value, we can re-use xmlStringResource struct
-	Items []xmlStringResource `xml:"item"`
-	xmlTranslatable
+	if watch && (writeStubs || stepSummary || githubComment || githubIssue) {
+		fatal("'--watch' can't be used with '--write-stubs' or the GitHub integrations")
+	}
 }
 
-// localeStringsMap declares the type to map locales => string_name => stringResource
-type localeStringsMap map[string]map[string]xmlStringResource
-
-// stringResource declares the output structure for a single string resource.
-type stringResource struct {
-	Name            string   `json:"name"`
-	Value           string   `json:"value"`
-	MissingLocales  []string `json:"missing_locales"`
-	OutdatedLocales []string `json:"outdated_locales"`
-}
+func main() {
+	aliases, err := resources.ParseLocaleAliases(resources.DefaultLocaleAliases, localeAliases)
+	if err != nil {
+		fatal(err)
+	}
 
-// MissingLocalesString joins the MissingLocales slice using ", " separator
-func (res stringResource) MissingLocalesString() string {
-	if len(res.MissingLocales) == 0 {
-		return "-"
+	mode, err := report.ParseMode(reportMode)
+	if err != nil {
+		fatal(err)
 	}
 
-	return strings.Join(res.MissingLocales, ", ")
-}
+	group, err := report.ParseGroupBy(groupBy)
+	if err != nil {
+		fatal(err)
+	}
 
-// OutdatesLocalesString joins the OutdatesLocales slice using ", " separator
-func (res stringResource) OutdatedLocalesString() string {
-	if len(res.OutdatedLocales) == 0 {
-		return "-"
+	stubs, err := report.ParseStubValue(stubValue)
+	if err != nil {
+		fatal(err)
 	}
 
-	return strings.Join(res.OutdatedLocales, ", ")
-}
+	pathFilter := resources.PathFilter{Include: includePaths, Exclude: excludePaths}
+	dirs, err := expandProjectDirs(projectDirs, pathFilter)
+	if err != nil {
+		fatal(err)
+	}
 
-// stringResources is a named type for stringResource slice that implements
-// the sort.Interface for sorting slices.
-type stringResources []stringResource
+	if githubActions {
+		setActionSourceBaseURL(dirs)
+	}
 
-func (res stringResources) Len() int           { return len(res) }
-func (res stringResources) Swap(i, j int)      { res[i], res[j] = res[j], res[i] }
-func (res stringResources) Less(i, j int) bool { return res[i].Name < res[j].Name }
+	if len(dirs) > 1 && !mergeProjects && group == report.GroupByNone {
+		group = report.GroupByProject
+	}
 
-// defaultLocale declares the constant to identify default string resources (resources
-// in 'values' [no suffix] directory)
-const defaultLocale = "default"
+	opts := report.Options{
+		OutdatedLocales:     outdatedLocales,
+		LocaleAliases:       aliases,
+		CheckLocaleConfig:   checkLocaleConf,
+		CheckLocaleSupport:  checkSupport,
+		CheckStale:          checkStale,
+		CheckPlaceholders:   checkFormat,
+		CheckIdentical:      checkIdentical,
+		IgnoreIdentical:     ignoreIdentical,
+		RespectToolsIgnore:  toolsIgnore,
+		PathFilter:          pathFilter,
+		IgnoreStrings:       ignoreStrings,
+		ListIgnored:         listIgnored,
+		SinceRef:            sinceRef,
+		DefaultLocale:       defaultLocale,
+		Locales:             locales,
+		GradleLocaleFilters: gradleLocales,
+		Jobs:                jobs,
+	}
 
-var (
-	projectDir      string // root directory of the Android Project
-	outdatedLocales bool   // if true, also print potentially outdated locales
-	outputFormat    string // output format, must be one of markdown or json
-	markdownTitle   string // heading for markdown content
-	githubActions   bool   // if true, also call setGitHubActionsOutput to set action output
-)
+	renderOpts := report.RenderOptions{
+		Title:         markdownTitle,
+		Mode:          mode,
+		GroupBy:       group,
+		SourceBaseURL: sourceBaseURL,
+		LocaleNames:   localeNames,
+	}
 
-func init() {
-	pflag.CommandLine.SortFlags = false
-	pflag.StringVar(&projectDir, "project-dir", ".", "Android Project's root directory")
-	pflag.BoolVar(&outdatedLocales, "outdated-locales", true, "If true, find potentially outdated translations")
-	pflag.StringVar(&outputFormat, "output-format", "json", "Output format. Must be 'json' or 'markdown'")
-	pflag.StringVar(&markdownTitle, "markdown-title", "Android Translations", "Title for the Markdown content")
-	pflag.BoolVar(&githubActions, "github-actions", false, "Indicates if the runtime is GitHub Actions")
-	pflag.Parse()
+	if watch {
+		fatal(watchProjects(dirs, opts, renderOpts))
+	}
 
-	if outputFormat != "json" && outputFormat != "markdown" {
-		fatal(fmt.Sprintf("unknow output format %s", outputFormat))
+	var stream *jsonLinesStream
+	if outputFormat == "jsonl" {
+		if stream, err = streamJSONLines(&opts, renderOpts, len(dirs) > 1); err != nil {
+			fatal(err)
+		}
 	}
-}
 
-func main() {
-	valuesFiles, err := findValuesFiles(projectDir)
+	r, err := scanProjects(dirs, opts)
 	if err != nil {
 		fatal(err)
 	}
 
-	localeStrings, err := findTranslatableStrings(valuesFiles)
+	for _, warning := range r.Warnings {
+		fmt.Fprintln(os.Stderr, "warning:", warning)
+	}
+
+	if writeBaseline != "" {
+		if err := report.NewBaseline(r).Save(writeBaseline); err != nil {
+			fatal(err)
+		}
+
+		fmt.Fprintln(os.Stderr, "wrote baseline to", writeBaseline)
+	}
+
+	if baselineFile != "" {
+		if err := applyBaseline(r); err != nil {
+			fatal(err)
+		}
+	}
+
+	if writeStubs {
+		files, err := report.WriteStubs(r, report.StubOptions{Value: stubs, Comment: stubComment})
+		if err != nil {
+			fatal(err)
+		}
+
+		for _, file := range files {
+			fmt.Fprintln(os.Stderr, "wrote stubs to", file)
+		}
+	}
+
+	output, err := printReport(r, renderOpts, stream)
 	if err != nil {
 		fatal(err)
 	}
 
-	defaultStrings, ok := localeStrings[defaultLocale]
-	if !ok { // shouldn't be true for valid input
-		fatal("unable to find string resources for default locale")
+	if githubActions {
+		if err := github.SetOutput("report", output); err != nil {
+			fatal(err)
+		}
 	}
 
-	report := make([]stringResource, 0)
-	for _, str := range defaultStrings {
-		strResource := stringResource{
-			Name:            str.Name,
-			Value:           strings.TrimSpace(str.Value),
-			MissingLocales:  []string{},
-			OutdatedLocales: []string{},
+	if stepSummary {
+		if err := appendStepSummary(r, renderOpts); err != nil {
+			fatal(err)
 		}
+	}
 
-		for locale := range localeStrings {
-			if localeStr, ok := localeStrings[locale][str.Name]; !ok {
-				strResource.MissingLocales = append(strResource.MissingLocales, locale)
-			} else if localeStr.LastModified.Before(str.LastModified) {
-				strResource.OutdatedLocales = append(strResource.OutdatedLocales, locale)
-			}
+	if githubComment || githubIssue {
+		if err := postGitHubReport(r, renderOpts); err != nil {
+			fatal(err)
 		}
+	}
 
-		if len(strResource.MissingLocales)+len(strResource.OutdatedLocales) > 0 {
-			report = append(report, strResource)
-		}
+	if printErrors(r) {
+		os.Exit(1)
 	}
+}
 
-	sort.Sort(stringResources(report))
+// printReport renders the report in the output format, prints it to the standard
+// output and, if 'outputFile' is set, writes it to the file. With JSON Lines, the report
+// was already streamed while scanning the projects and only the given stream is
+// finished. It returns the rendered report.
+func printReport(r *report.Report, opts report.RenderOptions, stream *jsonLinesStream) (string, error) {
 	var output string
+	var err error
 	switch outputFormat {
 	case "json":
-		output = mustRenderJSON(report)
+		output, err = report.RenderJSON(r, opts)
+		break
+	case "jsonl":
+		output, err = stream.finish(r)
 		break
 	case "markdown":
-		output = mustRenderMarkdown(markdownTitle, report)
+		output, err = report.RenderMarkdown(r, opts)
+		break
+	case "html":
+		output, err = report.RenderHTML(r, opts)
+		break
+	case "sarif":
+		output, err = report.RenderSARIF(r)
+		break
+	case "xliff":
+		output, err = writeXLIFFFiles(r, outputDir, xliffVersion)
 		break
 	}
 
-	if githubActions {
-		setGitHubActionsOutput("report", output)
-		fmt.Println()
+	if err != nil {
+		return "", err
 	}
 
-	fmt.Println(output)
-}
+	if outputFormat != "jsonl" {
+		fmt.Println(output)
+	}
 
-// fatal is a convenience function that calls 'fmt.Println' with 'msg' followed by an
-// 'os.Exit(1)' invocation.
-func fatal(msg interface{}) {
-	fmt.Fprintln(os.Stderr, "error:", msg)
-	os.Exit(1)
+	if outputFile != "" && outputFormat != "jsonl" {
+		if err := ioutil.WriteFile(outputFile, []byte(output), 0644); err != nil {
+			return "", errors.Wrapf(err, "unable to write file at %s", outputFile)
+		}
+	}
+
+	return output, nil
 }
 
-// findValuesFiles finds XML files in 'path/**/*/values*'. This function should be
-// compatible with cases where multiple resource directories are in use.
-func findValuesFiles(path string) ([]string, error) {
-	files, err := ioutil.ReadDir(path)
-	if err != nil {
-		return nil, errors.Wrapf(err, "unable to read directory %s", path)
+// printErrors prints the configuration errors, the placeholder mismatches and the
+// failed gates of the report. It returns true if any gates failed.
+func printErrors(r *report.Report) bool {
+	for _, configError := range r.ConfigErrors {
+		fmt.Fprintln(os.Stderr, "error:", configError)
+	}
+
+	if mismatchCount := r.PlaceholderMismatchCount(); mismatchCount > 0 {
+		fmt.Fprintf(os.Stderr, "warning: found %d translations with mismatching placeholders\n", mismatchCount)
 	}
 
-	valuesFiles := make([]string, 0)
-	for _, file := range files {
-		filePath := filepath.Join(path, file.Name())
-		if isGitIgnored(path, filePath) {
-			continue
+	failures := r.CheckThresholds(thresholds)
+	for _, failure := range failures {
+		fmt.Fprintln(os.Stderr, "error: gate failed:", failure)
+	}
+
+	return len(failures) > 0
+}
+
+// expandProjectDirs expands the glob patterns in the given project directories to the
+// matching directories. The hidden directories, e.g. '.git', and the directories
+// without any values files matching the given filter are skipped while expanding the
+// patterns. The result is sorted and doesn't contain duplicates.
+func expandProjectDirs(patterns []string, filter resources.PathFilter) ([]string, error) {
+	dirs := make([]string, 0, len(patterns))
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches := []string{pattern}
+		isGlob := strings.ContainsAny(pattern, "*?[")
+		if isGlob {
+			var err error
+			if matches, err = filepath.Glob(pattern); err != nil {
+				return nil, errors.Wrapf(err, "invalid project directory pattern %q", pattern)
+			}
 		}
 
-		if file.IsDir() {
-			moreValuesFiles, err := findValuesFiles(filePath)
-			if err != nil {
-				return nil, err
+		found := false
+		for _, match := range matches {
+			if info, err := os.Stat(match); err != nil || !info.IsDir() {
+				continue
+			}
+
+			if isGlob && strings.HasPrefix(filepath.Base(match), ".") {
+				continue
 			}
 
-			valuesFiles = append(valuesFiles, moreValuesFiles...)
-		} else {
-			if isValuesFile(filePath) {
-				valuesFiles = append(valuesFiles, filePath)
+			if isGlob {
+				valuesFiles, err := resources.FindValuesFiles(match, filter)
+				if err != nil {
+					return nil, err
+				} else if len(valuesFiles) == 0 {
+					continue
+				}
 			}
+
+			found = true
+			if match = filepath.Clean(match); !seen[match] {
+				seen[match] = true
+				dirs = append(dirs, match)
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("no Android project directory found at %s", pattern)
 		}
 	}
 
-	return valuesFiles, nil
+	sort.Strings(dirs)
+	return dirs, nil
 }
 
-// isValuesFile checks the prefix on the parent of the given path. It also checks
-// the file extension of the path. If the file name is equal to doNotTranslateFileName,
-// it returns false. If the prefix equals 'values' and file extension
-// equals 'xml', it returns true. False otherwise.
-func isValuesFile(path string) bool {
-	if doNotTranslateFileName == filepath.Base(path) {
-		return false
+// scanProjects scans the given project directories. If there are several directories,
+// their reports are merged.
+func scanProjects(dirs []string, opts report.Options) (*report.Report, error) {
+	reports := make([]*report.Report, 0, len(dirs))
+	for _, dir := range dirs {
+		r, err := report.Scan(dir, opts)
+		if err != nil {
+			if len(dirs) > 1 {
+				err = errors.Wrapf(err, "unable to scan project at %s", dir)
+			}
+
+			return nil, err
+		}
+
+		reports = append(reports, r)
+	}
+
+	if len(reports) == 1 {
+		return reports[0], nil
 	}
 
-	parent := filepath.Base(filepath.Dir(path))
-	return strings.HasPrefix(parent, "values") && strings.EqualFold(".xml", filepath.Ext(path))
+	return report.MergeReports(dirs, reports), nil
 }
 
-// findTranslatableStrings looks for '<string>' tags with '<resources>' tag as its root
-// in given files. It parses all the string tags without 'translatable="fasle"' attribute.
-// It returns a mapping of locale to their strings where locale is suffix of 'values-'.
-// If no suffix is present, i.e. 'values', defaultLocale constant is used to identify those
-// values.
-func findTranslatableStrings(files []string) (localeStringsMap, error) {
-	strResources := make(localeStringsMap, 0)
-	for _, file := range files {
-		content, err := ioutil.ReadFile(file)
-		if err != nil {
-			return nil, errors.Wrapf(err, "unable to read file at %s", file)
-		}
+// watchDelay is the time to wait for more changes after a values file changes before
+// scanning the projects again, e.g. since editors often write a file in several steps.
+const watchDelay = 200 * time.Millisecond
+
+// watchProjects scans the given project directories and prints the report. It then
+// watches the directories of their values files, and their resource directories for
+// new values directories, and scans the projects again whenever they change. Only the
+// changed values files are parsed again. It only returns if the watcher fails or the
+// first scan fails. The errors of the later scans are printed, e.g. while a values file
+// is being edited and isn't well-formed.
+func watchProjects(dirs []string, opts report.Options, renderOpts report.RenderOptions) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, "unable to create file watcher")
+	}
 
-		resources := &xmlStringResources{}
-		err = xml.Unmarshal(content, resources)
-		if err != nil {
-			return nil, errors.Wrapf(err, "unable to parse XML file at %s", file)
+	defer watcher.Close()
+	opts.Cache = resources.NewParseCache()
+	watched := make(map[string]bool)
+	for {
+		var stream *jsonLinesStream
+		if outputFormat == "jsonl" {
+			if stream, err = streamJSONLines(&opts, renderOpts, len(dirs) > 1); err != nil {
+				return err
+			}
 		}
 
-		locale := getLocaleForValuesFile(file)
-		strResCount := len(resources.Strings) + len(resources.StringArrays)
-		if _, ok := strResources[locale]; !ok && strResCount > 0 {
-			strResources[locale] = map[string]xmlStringResource{}
+		r, err := scanProjects(dirs, opts)
+		if err != nil && stream != nil {
+			stream.close()
 		}
 
-		for _, str := range resources.Strings {
-			if !str.IsTranslatable() {
-				continue
+		if err != nil && len(watched) == 0 {
+			return err
+		} else if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		} else {
+			for _, warning := range r.Warnings {
+				fmt.Fprintln(os.Stderr, "warning:", warning)
+			}
+
+			if baselineFile != "" {
+				if err := applyBaseline(r); err != nil {
+					return err
+				}
 			}
 
-			start, count, err := getLineRange(content, str.Value)
-			if err == nil {
-				str.LastModified, err = getLastModifiedTime(file, start, count)
+			if _, err := printReport(r, renderOpts, stream); err != nil {
+				return err
 			}
 
-			if err != nil {
-				fmt.Fprintln(os.Stderr, "warning:", err)
-				str.LastModified = time.Now()
+			printErrors(r)
+			for _, file := range r.ValuesFiles {
+				valuesDir := filepath.Dir(file)
+				for _, dir := range []string{valuesDir, filepath.Dir(valuesDir)} {
+					if watched[dir] {
+						continue
+					}
+
+					if err := watcher.Add(dir); err != nil {
+						return errors.Wrapf(err, "unable to watch directory at %s", dir)
+					}
+
+					watched[dir] = true
+				}
 			}
+		}
 
-			strResources[locale][str.Name] = str
+		fmt.Fprintf(os.Stderr, "watching %d directories for changes...\n", len(watched))
+		file, err := waitForChange(watcher, watched)
+		if err != nil {
+			return err
 		}
 
-		for _, strArr := range resources.StringArrays {
-			if !strArr.IsTranslatable() {
-				continue
+		fmt.Fprintln(os.Stderr, "scanning again after a change to", file)
+	}
+}
+
+// waitForChange blocks until a values file or directory changes in the directories
+// watched by 'watcher' and no more changes follow within 'watchDelay'. It removes the
+// directories that were removed or renamed from 'watched'. It returns the path of the
+// last changed file.
+func waitForChange(watcher *fsnotify.Watcher, watched map[string]bool) (string, error) {
+	var changed string
+	var delay <-chan time.Time
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return "", errors.New("file watcher was closed")
 			}
 
-			for i, strArrItem := range strArr.Items {
-				strArrItem.Name = fmt.Sprintf("%s[%d]", strArr.Name, i)
-				start, count, err := getLineRange(content, strArrItem.Value)
-				if err == nil {
-					strArrItem.LastModified, err = getLastModifiedTime(file, start, count)
-				}
+			if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				delete(watched, event.Name)
+			}
 
-				if err != nil {
-					fmt.Fprintln(os.Stderr, "warning:", err)
-					strArrItem.LastModified = time.Now()
-				}
+			name := filepath.Base(event.Name)
+			if event.Op == fsnotify.Chmod || (filepath.Ext(name) != ".xml" && !strings.HasPrefix(name, "values")) {
+				continue
+			}
 
-				strResources[locale][strArrItem.Name] = strArrItem
+			changed = event.Name
+			delay = time.After(watchDelay)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return "", errors.New("file watcher was closed")
 			}
+
+			return "", errors.Wrap(err, "unable to watch files")
+		case <-delay:
+			return changed, nil
 		}
 	}
+}
 
-	return strResources, nil
+// setActionDefaults sets the defaults of the action inputs to the flags that weren't set
+// on the command-line. Unlike setting them as the defaults in 'action.yaml', they don't
+// take precedence over the config file.
+func setActionDefaults() {
+	if !pflag.CommandLine.Changed("output-format") {
+		outputFormat = "markdown"
+	}
+
+	if !pflag.CommandLine.Changed("markdown-title") {
+		markdownTitle = "Missing Translations"
+	}
 }
 
-// getLocaleForValuesFile returns the suffix after 'values-'. If no suffix is present,
-// e.g. 'values', it returns the defaultLocale constant.
-func getLocaleForValuesFile(path string) string {
-	parent := filepath.Base(filepath.Dir(path))
-	if strings.EqualFold(parent, "values") {
-		return defaultLocale
+// setActionSourceBaseURL sets the URL of the directory, that the files in the report of
+// the given project directories are relative to, as the source base URL if it wasn't
+// set on the command-line or in the config file. The files are relative to the project
+// directory if there is only one, and to the current working directory otherwise.
+func setActionSourceBaseURL(dirs []string) {
+	if pflag.CommandLine.Changed("source-base-url") || sourceBaseURL != "" {
+		return
 	}
 
-	split := strings.SplitN(parent, "-", 2)
-	if len(split) < 2 { // edge case. shouldn't be true for valid input
-		return defaultLocale
+	dir := "."
+	if len(dirs) == 1 {
+		dir = dirs[0]
 	}
 
-	return split[1]
+	sourceBaseURL = github.SourceBaseURL(dir)
 }
 
-// isGitIgnored checks if the given path is ignored from being tracked by 'git'. 'workingDir'
-// is used provide additional to 'git' command. It returns false, if 'workingDir' is not an
-// ancestor of the given file path.
-func isGitIgnored(workingDir, file string) bool {
-	relFilePath, err := filepath.Rel(workingDir, file)
-	if err != nil {
-		return false
+// loadConfig loads the config file at 'configFile' or, if it is empty, the config file
+// in the project directory and applies its values to the flags that weren't set
+// explicitly on the command-line. If several project directories are set, the config
+// file is looked up in the current working directory.
+func loadConfig() error {
+	path := configFile
+	if path == "" {
+		dir := "."
+		if len(projectDirs) == 1 && !strings.ContainsAny(projectDirs[0], "*?[") {
+			dir = projectDirs[0]
+		}
+
+		var err error
+		if path, err = config.Find(dir); err != nil || path == "" {
+			return err
+		}
 	}
 
-	cmd := exec.Command("git", "check-ignore", relFilePath)
-	cmd.Dir = workingDir
-	if err := cmd.Run(); err != nil {
-		return false
+	values, err := config.Load(path)
+	if err != nil {
+		return err
 	}
 
-	return true
+	return config.Apply(pflag.CommandLine, values)
 }
 
-// mustRenderJSON marshals the given value as JSON. It panics on encountering an error
-// while marshaling JSON.
-func mustRenderJSON(v interface{}) string {
-	content, err := json.MarshalIndent(v, "", "  ")
+// applyBaseline suppresses the issues listed in the baseline file in the report. If
+// 'pruneBaseline' is true, it also removes the issues that no longer apply from the
+// baseline file.
+func applyBaseline(r *report.Report) error {
+	baseline, err := report.LoadBaseline(baselineFile)
 	if err != nil {
-		panic(errors.Wrap(err, "failed to marshal content as JSON"))
+		return err
+	}
+
+	applied := r.ApplyBaseline(baseline)
+	fmt.Fprintf(os.Stderr, "suppressed %d known issues listed in %s\n", len(applied.Issues), baselineFile)
+	fixed := len(baseline.Issues) - len(applied.Issues)
+	if fixed == 0 {
+		return nil
+	}
+
+	if !pruneBaseline {
+		const warningFmt = "warning: %d issues listed in %s no longer apply, use '--prune-baseline' to remove them\n"
+		fmt.Fprintf(os.Stderr, warningFmt, fixed, baselineFile)
+		return nil
+	}
+
+	if err := applied.Save(baselineFile); err != nil {
+		return err
 	}
 
-	return string(content)
+	fmt.Fprintf(os.Stderr, "pruned %d issues that no longer apply from %s\n", fixed, baselineFile)
+	return nil
 }
 
-// mustRenderMarkdown tries render markdown content using on a const template.
-// If there is an error when rendering the template, it panics.
-func mustRenderMarkdown(title string, data []stringResource) string {
-	mdTemplate, err := template.New("markdown").Parse(`# {{ .title }}
-
-{{ if eq .length 0 -}}
-No missing {{- if eq .outdated_on true }} or outdated {{- end }} translations found.
-{{ else -}}
-{{ .table }}
-{{- end }}
-_Generated using [Android Translations][1] GitHub action._
-
-[1]: https://github.com/ashutoshgngwr/android-translations
-`)
-
-	var content bytes.Buffer
-	err = mdTemplate.Execute(&content, map[string]interface{}{
-		"title":       title,
-		"length":      len(data),
-		"outdated_on": outdatedLocales,
-		"table":       renderMarkdownTable(data),
-	})
+// fatal is a convenience function that calls 'fmt.Println' with 'msg' followed by an
+// 'os.Exit(1)' invocation.
+func fatal(msg interface{}) {
+	fmt.Fprintln(os.Stderr, "error:", msg)
+	os.Exit(1)
+}
 
+// writeXLIFFFiles renders the missing translations in the report as XLIFF documents
+// and writes them to 'dir' with one file per locale named after its language tag. It
+// returns the paths of the written files separated by new lines.
+func writeXLIFFFiles(r *report.Report, dir, version string) (string, error) {
+	documents, err := report.RenderXLIFF(r, version)
 	if err != nil {
-		panic(errors.Wrap(err, "unable to render data as markdown"))
+		return "", err
 	}
 
-	return content.String()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", errors.Wrapf(err, "unable to create directory %s", dir)
+	}
+
+	files := make([]string, 0, len(documents))
+	for locale, document := range documents {
+		file := filepath.Join(dir, resources.LocaleToLanguageTag(locale)+".xlf")
+		if err := ioutil.WriteFile(file, []byte(document), 0644); err != nil {
+			return "", errors.Wrapf(err, "unable to write file at %s", file)
+		}
+
+		files = append(files, file)
+	}
+
+	sort.Strings(files)
+	return strings.Join(files, "\n"), nil
 }
 
-// renderMarkdownTable pretty prints the slice of stringResource as Markdown
-// table to be used with Markdown format.
-func renderMarkdownTable(data []stringResource) string {
-	var tableContent bytes.Buffer
-	table := tablewriter.NewWriter(&tableContent)
-	table.SetBorders(tablewriter.Border{Left: true, Right: true})
-	table.SetCenterSeparator("|")
-
-	header := []string{"#", "Name", "Default Value", "Missing Locales"}
-	if outdatedLocales {
-		header = append(header, "Potentially Outdated Locales")
-	}
-
-	table.SetHeader(header)
-	for i, item := range data {
-		row := []string{
-			fmt.Sprintf("%d", 1+i),
-			fmt.Sprintf("`%s`", item.Name),
-			item.Value,
-			item.MissingLocalesString(),
+// jsonLinesStream streams the report as JSON Lines to the standard output and, if
+// 'outputFile' is set, to the file while the projects are scanned. With GitHub Actions,
+// the lines are also kept to set them as the action output.
+type jsonLinesStream struct {
+	writer *report.JSONLinesWriter
+	file   *os.File
+	lines  *bytes.Buffer
+}
+
+// streamJSONLines creates a jsonLinesStream and sets its callbacks in the scan options.
+// The known issues listed in the baseline file, if set, aren't streamed. 'merged' must be
+// true if several projects are scanned.
+func streamJSONLines(opts *report.Options, renderOpts report.RenderOptions, merged bool) (*jsonLinesStream, error) {
+	var baseline *report.Baseline
+	if baselineFile != "" {
+		var err error
+		if baseline, err = report.LoadBaseline(baselineFile); err != nil {
+			return nil, err
 		}
+	}
 
-		if outdatedLocales {
-			row = append(row, item.OutdatedLocalesString())
+	stream := &jsonLinesStream{}
+	writers := []io.Writer{os.Stdout}
+	if outputFile != "" {
+		file, err := os.Create(outputFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to create file at %s", outputFile)
 		}
 
-		table.Append(row)
+		stream.file = file
+		writers = append(writers, file)
+	}
+
+	if githubActions {
+		stream.lines = &bytes.Buffer{}
+		writers = append(writers, stream.lines)
 	}
 
-	table.Render()
-	return tableContent.String()
+	stream.writer = report.NewJSONLinesWriter(io.MultiWriter(writers...), renderOpts, baseline, merged)
+	opts.Started, opts.Found = stream.writer.Started, stream.writer.Found
+	return stream, nil
 }
 
-// setGitHubActionsOutput sets the output variable for Github Actions runtime.
-// This output can be used by other steps in a workflow.
-func setGitHubActionsOutput(key, value string) {
-	value = strings.ReplaceAll(value, "%", "%25")
-	value = strings.ReplaceAll(value, "\r", "%0D")
-	value = strings.ReplaceAll(value, "\n", "%0A")
-	fmt.Printf("::set-output name=%s::%s\n", key, value)
+// finish writes the coverage of the complete report and closes the stream. It returns
+// the streamed lines if they were kept.
+func (stream *jsonLinesStream) finish(r *report.Report) (string, error) {
+	err := stream.writer.Finish(r)
+	if closeErr := stream.close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil || stream.lines == nil {
+		return "", err
+	}
+
+	return stream.lines.String(), nil
 }
 
-// getLastModifiedTime returns the last modified time of the given line range in the
-// given file using 'git blame'.
-func getLastModifiedTime(file string, lineStart, lineCount int) (time.Time, error) {
-	const errFmt = "unable to find last modified time, file: %q, start: %d, count: %d"
-	const cmdFmt = "git blame -p -L %d,+%d %s | grep committer-time | awk '{ print $2 }'"
-
-	var stdoutBuffer bytes.Buffer
-	command := fmt.Sprintf(cmdFmt, lineStart, lineCount, filepath.Base(file))
-	cmd := exec.Command("sh", "-c", command)
-	cmd.Dir = filepath.Dir(file)
-	cmd.Stdout = &stdoutBuffer
-	if err := cmd.Run(); err != nil {
-		return time.Time{}, errors.Wrapf(err, errFmt, file, lineStart, lineCount)
-	}
-
-	// should handle case where multiline blame returns multiple commits and thus
-	// multiple committer-time fields
-	output := strings.TrimSpace(stdoutBuffer.String())
-	var latestTimestamp int64
-	for _, timestampStr := range strings.Split(output, "\n") {
-		timestamp, err := strconv.ParseInt(timestampStr, 10, 64)
+// close closes the output file of the stream, if any.
+func (stream *jsonLinesStream) close() error {
+	if stream.file == nil {
+		return nil
+	}
+
+	if err := stream.file.Close(); err != nil {
+		return errors.Wrapf(err, "unable to write file at %s", outputFile)
+	}
+
+	return nil
+}
+
+// appendStepSummary renders the report as Markdown and appends it to the job summary.
+func appendStepSummary(r *report.Report, opts report.RenderOptions) error {
+	markdown, err := report.RenderMarkdown(r, opts)
+	if err != nil {
+		return err
+	}
+
+	return github.AppendStepSummary(markdown)
+}
+
+// postGitHubReport renders the report as Markdown and posts it as a comment on the pull
+// request that triggered the workflow and/or as a tracking issue. Both are identified
+// by a hidden marker containing the Markdown title, so they are updated on every run.
+func postGitHubReport(r *report.Report, opts report.RenderOptions) error {
+	client, err := github.NewClientFromEnv()
+	if err != nil {
+		return err
+	}
+
+	markdown, err := report.RenderMarkdown(r, opts)
+	if err != nil {
+		return err
+	}
+
+	marker := fmt.Sprintf("<!-- android-translations: %s -->", opts.Title)
+	if githubComment {
+		number, err := github.FindEventIssueNumber()
 		if err != nil {
-			return time.Time{}, errors.Wrapf(err, errFmt, file, lineStart, lineCount)
+			return err
 		}
 
-		if timestamp > latestTimestamp {
-			latestTimestamp = timestamp
+		if number == 0 {
+			fmt.Fprintln(os.Stderr, "warning: not commenting since the event isn't related to a pull request")
+		} else if err := client.CreateOrUpdateComment(number, marker, markdown); err != nil {
+			return err
 		}
 	}
 
-	return time.Unix(latestTimestamp, 0), nil
-}
+	if githubIssue {
+		if r.Coverage.Missing > issueThreshold {
+			_, err = client.CreateOrUpdateIssue(marker, issueTitle, markdown)
+		} else {
+			_, err = client.CloseIssue(marker, markdown)
+		}
+	}
 
-// getLineRange returns the line range of the first occurrence of 'searchTerm' in
-// 'content'. 'searchTerm' can be a multiline string. It returns the following
-// positional values
-// 1. start: line number where searchTerm occurrence started
-// 2. count: total line count of the searchTerm itself.
-// 3. error: if the there was error in reading the file or find the search term
-func getLineRange(fileContent []byte, searchTerm string) (int, int, error) {
-	chunks := strings.Split(string(fileContent), searchTerm)
-	if len(chunks) < 2 {
-		const errFmt = "searchTerm: %q is not found"
-		return 0, 0, fmt.Errorf(errFmt, searchTerm)
-	}
-
-	start := 1 + strings.Count(chunks[0], "\n")
-	count := 1 + strings.Count(searchTerm, "\n")
-	return start, count, nil
+	return err
 }
@@ -0,0 +1,167 @@
+// Package config loads the options from an optional config file and applies them to
+// the command-line flags. The keys in the config file are the names of the flags,
+// e.g. 'project-dir' and 'required-locales', and the flags explicitly set on the
+// command-line take precedence over the values in the config file.
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+)
+
+// FileNames declares the names of the config files in the order they are looked up.
+var FileNames = []string{".android-translations.yml", ".android-translations.yaml"}
+
+// Values declares the type to map option names to their values in a config file.
+type Values map[string]interface{}
+
+// PathOptions declares the options whose values are file paths. The relative paths in a
+// config file are relative to the directory of the config file. The options mapped to
+// true accept several comma-separated paths.
+var PathOptions = map[string]bool{
+	"project-dir":    true,
+	"output-dir":     false,
+	"output-file":    false,
+	"baseline":       false,
+	"write-baseline": false,
+}
+
+// Find returns the path of the first config file in 'dir' with one of the FileNames. It
+// returns an empty path if none of the files exists.
+func Find(dir string) (string, error) {
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", errors.Wrapf(err, "unable to stat file at %s", path)
+		}
+
+		if !info.IsDir() {
+			return path, nil
+		}
+	}
+
+	return "", nil
+}
+
+// Load reads and parses the YAML config file at the given path. The relative paths of
+// the PathOptions are resolved against the directory of the file, so they don't depend
+// on the current working directory.
+func Load(path string) (Values, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read file at %s", path)
+	}
+
+	values := make(Values)
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse YAML file at %s", path)
+	}
+
+	for name, list := range PathOptions {
+		if value, ok := values[name]; ok {
+			values[name] = resolvePaths(filepath.Dir(path), value, list)
+		}
+	}
+
+	return values, nil
+}
+
+// resolvePaths joins 'dir' and the given relative path, or each of the relative paths
+// if the value is a list or, with 'list', a comma-separated string. Absolute and empty
+// paths are returned as is.
+func resolvePaths(dir string, value interface{}, list bool) interface{} {
+	switch v := value.(type) {
+	case string:
+		if list && strings.Contains(v, ",") {
+			paths := strings.Split(v, ",")
+			for i := range paths {
+				paths[i] = resolvePaths(dir, strings.TrimSpace(paths[i]), false).(string)
+			}
+
+			return strings.Join(paths, ",")
+		}
+
+		if v == "" || filepath.IsAbs(v) {
+			return v
+		}
+
+		return filepath.Join(dir, v)
+	case []interface{}:
+		paths := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			paths = append(paths, resolvePaths(dir, item, false))
+		}
+
+		return paths
+	default:
+		return value
+	}
+}
+
+// Apply sets the flags in the given set that weren't changed on the command-line to
+// their values in the config. A flag set on the command-line always takes precedence,
+// even if it is set to its default value. Lists replace the default values of the
+// slice flags and maps are converted to lists of 'key=value' pairs. It returns an error
+// if the config contains an unknown option or an invalid value.
+func Apply(flags *pflag.FlagSet, values Values) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		flag := flags.Lookup(name)
+		if flag == nil {
+			return fmt.Errorf("unknown option %q in config file", name)
+		}
+
+		if flag.Changed || values[name] == nil {
+			continue
+		}
+
+		if err := setFlagValue(flag, values[name]); err != nil {
+			return errors.Wrapf(err, "invalid value for option %q in config file", name)
+		}
+	}
+
+	return nil
+}
+
+// setFlagValue sets the given value to the flag.
+func setFlagValue(flag *pflag.Flag, value interface{}) error {
+	var items []string
+	switch v := value.(type) {
+	case []interface{}:
+		items = make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+	case map[interface{}]interface{}:
+		items = make([]string, 0, len(v))
+		for key, item := range v {
+			items = append(items, fmt.Sprintf("%v=%v", key, item))
+		}
+
+		sort.Strings(items)
+	default:
+		return flag.Value.Set(fmt.Sprint(v))
+	}
+
+	if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+		return sliceValue.Replace(items)
+	}
+
+	return flag.Value.Set(strings.Join(items, ","))
+}
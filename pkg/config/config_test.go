@@ -0,0 +1,131 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestLoadResolvesPaths(t *testing.T) {
+	dir := t.TempDir()
+	configDir := filepath.Join(dir, "config")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	absBaseline := filepath.Join(dir, "baseline.json")
+	content := `project-dir: [app, "../apps/*"]
+output-dir: translations
+output-file: ""
+baseline: ` + absBaseline + `
+write-baseline: ./baseline.json
+exclude-path: [library/**]
+`
+
+	path := filepath.Join(configDir, FileNames[0])
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := Values{
+		"project-dir":    []interface{}{filepath.Join(configDir, "app"), filepath.Join(dir, "apps", "*")},
+		"output-dir":     filepath.Join(configDir, "translations"),
+		"output-file":    "",
+		"baseline":       absBaseline,
+		"write-baseline": filepath.Join(configDir, "baseline.json"),
+		"exclude-path":   []interface{}{"library/**"},
+	}
+
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("Load() = %v, want %v", values, want)
+	}
+}
+
+func TestResolvePathsOfCommaSeparatedList(t *testing.T) {
+	want := filepath.Join("config", "app") + "," + filepath.Join("config", "lib")
+	if got := resolvePaths("config", "app, lib", true); got != want {
+		t.Errorf("resolvePaths() = %q, want %q", got, want)
+	}
+}
+
+func TestApply(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	format := flags.String("output-format", "markdown", "")
+	checkStale := flags.Bool("check-stale", false, "")
+	failOnMissing := flags.Bool("fail-on-missing", false, "")
+	minCoverage := flags.Float64("min-coverage-percent", 0, "")
+	locales := flags.StringSlice("required-locales", []string{"en"}, "")
+	aliases := flags.StringSlice("locale-aliases", []string{}, "")
+	title := flags.String("markdown-title", "Missing Translations", "")
+	if err := flags.Parse([]string{"--output-format=json", "--fail-on-missing=false"}); err != nil {
+		t.Fatal(err)
+	}
+
+	values := Values{
+		"output-format":        "html",
+		"check-stale":          true,
+		"fail-on-missing":      true,
+		"min-coverage-percent": 80.5,
+		"required-locales":     []interface{}{"de", "fr"},
+		"locale-aliases":       map[interface{}]interface{}{"no": "nb", "iw": "he"},
+		"markdown-title":       nil,
+	}
+
+	if err := Apply(flags, values); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	if *format != "json" {
+		t.Errorf("output-format = %q, want the command-line value %q", *format, "json")
+	}
+
+	if *failOnMissing {
+		t.Errorf("fail-on-missing = true, want the command-line value false")
+	}
+
+	if !*checkStale || *minCoverage != 80.5 {
+		t.Errorf("check-stale = %t, min-coverage-percent = %v, want the config values", *checkStale, *minCoverage)
+	}
+
+	if want := []string{"de", "fr"}; !reflect.DeepEqual(*locales, want) {
+		t.Errorf("required-locales = %q, want %q", *locales, want)
+	}
+
+	if want := []string{"iw=he", "no=nb"}; !reflect.DeepEqual(*aliases, want) {
+		t.Errorf("locale-aliases = %q, want %q", *aliases, want)
+	}
+
+	if *title != "Missing Translations" {
+		t.Errorf("markdown-title = %q, want the default value", *title)
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		values Values
+	}{
+		{name: "unknown option", values: Values{"output-fromat": "json"}},
+		{name: "invalid value", values: Values{"check-stale": "maybe"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			flags.String("output-format", "markdown", "")
+			flags.Bool("check-stale", false, "")
+			if err := Apply(flags, test.values); err == nil {
+				t.Error("Apply() error = nil, want an error")
+			}
+		})
+	}
+}
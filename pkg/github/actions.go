@@ -0,0 +1,104 @@
+package github
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SetOutput sets the output of the current step in GitHub Actions runtime by appending
+// it to the file at 'GITHUB_OUTPUT'. Values are written with a random heredoc
+// delimiter, so multi-line values are preserved as is. If 'GITHUB_OUTPUT' isn't set,
+// e.g. on older runners, it falls back to the deprecated 'set-output' command.
+func SetOutput(key, value string) error {
+	path := os.Getenv("GITHUB_OUTPUT")
+	if path == "" {
+		value = strings.ReplaceAll(value, "%", "%25")
+		value = strings.ReplaceAll(value, "\r", "%0D")
+		value = strings.ReplaceAll(value, "\n", "%0A")
+		fmt.Printf("::set-output name=%s::%s\n", key, value)
+		return nil
+	}
+
+	delimiter, err := randomDelimiter()
+	if err != nil {
+		return err
+	}
+
+	return appendToFile(path, fmt.Sprintf("%s<<%s\n%s\n%s\n", key, delimiter, value, delimiter))
+}
+
+// AppendStepSummary appends the given Markdown content to the job summary of the
+// current step, i.e. the file at 'GITHUB_STEP_SUMMARY'.
+func AppendStepSummary(markdown string) error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return errors.New("GITHUB_STEP_SUMMARY is not set")
+	}
+
+	return appendToFile(path, markdown+"\n")
+}
+
+// SourceBaseURL returns the URL of the given directory in the repository tree at the
+// commit that triggered the workflow, e.g. 'https://github.com/owner/repo/blob/<sha>/app'
+// for 'app'. The directory is resolved relative to 'GITHUB_WORKSPACE', the checkout of
+// the repository. It returns an empty string if the runtime isn't GitHub Actions or the
+// directory isn't in the workspace.
+func SourceBaseURL(dir string) string {
+	serverURL, repository, sha := os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_SHA")
+	if serverURL == "" || repository == "" || sha == "" {
+		return ""
+	}
+
+	workspace, err := filepath.Abs(os.Getenv("GITHUB_WORKSPACE"))
+	if err != nil {
+		return ""
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	relDir, err := filepath.Rel(workspace, absDir)
+	if err != nil || relDir == ".." || strings.HasPrefix(relDir, ".."+string(filepath.Separator)) {
+		return ""
+	}
+
+	url := fmt.Sprintf("%s/%s/blob/%s", serverURL, repository, sha)
+	if relDir != "." {
+		url += "/" + filepath.ToSlash(relDir)
+	}
+
+	return url
+}
+
+// randomDelimiter returns a heredoc delimiter that is unlikely to occur in any value.
+func randomDelimiter() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "unable to generate delimiter")
+	}
+
+	return "ghadelimiter_" + hex.EncodeToString(b), nil
+}
+
+// appendToFile appends the given content to the file at 'path'.
+func appendToFile(path, content string) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "unable to open file at %s", path)
+	}
+
+	if _, err := file.WriteString(content); err != nil {
+		file.Close()
+		return errors.Wrapf(err, "unable to write file at %s", path)
+	}
+
+	return errors.Wrapf(file.Close(), "unable to write file at %s", path)
+}
@@ -0,0 +1,46 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSourceBaseURL(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		workspace string
+		dir       string
+		want      string
+	}{
+		{name: "root", workspace: cwd, dir: ".", want: "https://github.com/owner/repo/blob/abc"},
+		{name: "project", workspace: cwd, dir: "app", want: "https://github.com/owner/repo/blob/abc/app"},
+		{name: "nested project", workspace: cwd, dir: "./apps/one/", want: "https://github.com/owner/repo/blob/abc/apps/one"},
+		{name: "absolute project", workspace: filepath.Dir(cwd), dir: cwd, want: "https://github.com/owner/repo/blob/abc/" + filepath.Base(cwd)},
+		{name: "outside workspace", workspace: cwd, dir: "..", want: ""},
+	}
+
+	t.Setenv("GITHUB_SERVER_URL", "https://github.com")
+	t.Setenv("GITHUB_REPOSITORY", "owner/repo")
+	t.Setenv("GITHUB_SHA", "abc")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("GITHUB_WORKSPACE", test.workspace)
+			if got := SourceBaseURL(test.dir); got != test.want {
+				t.Errorf("SourceBaseURL(%q) = %q, want %q", test.dir, got, test.want)
+			}
+		})
+	}
+
+	t.Run("not github actions", func(t *testing.T) {
+		t.Setenv("GITHUB_SHA", "")
+		if got := SourceBaseURL("."); got != "" {
+			t.Errorf("SourceBaseURL(\".\") = %q, want an empty string", got)
+		}
+	})
+}
@@ -0,0 +1,264 @@
+// Package github implements a minimal client for the GitHub REST API to post reports as
+// pull request comments and tracking issues. Comments and issues are identified by a
+// hidden marker in their body, so that they are updated instead of duplicated on every
+// run.
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultAPIURL is the URL of the GitHub REST API used if 'GITHUB_API_URL' isn't set.
+const DefaultAPIURL = "https://api.github.com"
+
+// pageSize is the number of items to request per page while listing.
+const pageSize = 100
+
+// Client declares a GitHub REST API client for a single repository.
+type Client struct {
+	// APIURL is the base URL of the GitHub REST API.
+	APIURL string
+
+	// Token is used to authenticate the requests.
+	Token string
+
+	// Repository is the 'owner/name' of the repository.
+	Repository string
+
+	// HTTPClient is used to send the requests.
+	HTTPClient *http.Client
+}
+
+// Issue declares the fields of issues and pull requests used by the client.
+type Issue struct {
+	Number      int             `json:"number"`
+	Title       string          `json:"title"`
+	Body        string          `json:"body"`
+	State       string          `json:"state"`
+	PullRequest json.RawMessage `json:"pull_request,omitempty"`
+}
+
+// Comment declares the fields of issue comments used by the client.
+type Comment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// NewClientFromEnv creates a client using the 'GITHUB_TOKEN', 'GITHUB_REPOSITORY' and
+// 'GITHUB_API_URL' environment variables set by GitHub Actions.
+func NewClientFromEnv() (*Client, error) {
+	client := &Client{
+		APIURL:     os.Getenv("GITHUB_API_URL"),
+		Token:      os.Getenv("GITHUB_TOKEN"),
+		Repository: os.Getenv("GITHUB_REPOSITORY"),
+		HTTPClient: http.DefaultClient,
+	}
+
+	if client.APIURL == "" {
+		client.APIURL = DefaultAPIURL
+	}
+
+	if client.Token == "" {
+		return nil, errors.New("GITHUB_TOKEN is not set")
+	}
+
+	if client.Repository == "" {
+		return nil, errors.New("GITHUB_REPOSITORY is not set")
+	}
+
+	return client, nil
+}
+
+// FindEventIssueNumber returns the number of the pull request or the issue in the
+// payload of the event that triggered the workflow, read from the file at
+// 'GITHUB_EVENT_PATH'. It returns 0 if the event isn't related to a pull request or an
+// issue.
+func FindEventIssueNumber() (int, error) {
+	path := os.Getenv("GITHUB_EVENT_PATH")
+	if path == "" {
+		return 0, errors.New("GITHUB_EVENT_PATH is not set")
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to read file at %s", path)
+	}
+
+	event := struct {
+		Number      int `json:"number"`
+		PullRequest struct {
+			Number int `json:"number"`
+		} `json:"pull_request"`
+		Issue struct {
+			Number int `json:"number"`
+		} `json:"issue"`
+	}{}
+
+	if err := json.Unmarshal(content, &event); err != nil {
+		return 0, errors.Wrapf(err, "unable to parse JSON file at %s", path)
+	}
+
+	for _, number := range []int{event.PullRequest.Number, event.Issue.Number, event.Number} {
+		if number > 0 {
+			return number, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// CreateOrUpdateComment updates the first comment on the given issue or pull request
+// whose body contains 'marker' with the given body. If there is no such comment, it
+// creates a new one. The marker is prepended to the body if it doesn't contain it.
+func (client *Client) CreateOrUpdateComment(number int, marker, body string) error {
+	body = withMarker(marker, body)
+	for page := 1; ; page++ {
+		comments := make([]Comment, 0)
+		path := fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=%d&page=%d", client.Repository, number, pageSize, page)
+		if err := client.do(http.MethodGet, path, nil, &comments); err != nil {
+			return err
+		}
+
+		for _, comment := range comments {
+			if strings.Contains(comment.Body, marker) {
+				path := fmt.Sprintf("/repos/%s/issues/comments/%d", client.Repository, comment.ID)
+				return client.do(http.MethodPatch, path, map[string]string{"body": body}, nil)
+			}
+		}
+
+		if len(comments) < pageSize {
+			break
+		}
+	}
+
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", client.Repository, number)
+	return client.do(http.MethodPost, path, map[string]string{"body": body}, nil)
+}
+
+// FindIssue returns the first open issue whose body contains 'marker'. It returns nil
+// if there is no such issue. Pull requests are skipped.
+func (client *Client) FindIssue(marker string) (*Issue, error) {
+	for page := 1; ; page++ {
+		issues := make([]Issue, 0)
+		path := fmt.Sprintf("/repos/%s/issues?state=open&per_page=%d&page=%d", client.Repository, pageSize, page)
+		if err := client.do(http.MethodGet, path, nil, &issues); err != nil {
+			return nil, err
+		}
+
+		for i := range issues {
+			if len(issues[i].PullRequest) == 0 && strings.Contains(issues[i].Body, marker) {
+				return &issues[i], nil
+			}
+		}
+
+		if len(issues) < pageSize {
+			return nil, nil
+		}
+	}
+}
+
+// CreateOrUpdateIssue updates the title and the body of the first open issue whose
+// body contains 'marker'. If there is no such issue, it creates a new one. The marker
+// is prepended to the body if it doesn't contain it. It returns the number of the
+// issue.
+func (client *Client) CreateOrUpdateIssue(marker, title, body string) (int, error) {
+	issue, err := client.FindIssue(marker)
+	if err != nil {
+		return 0, err
+	}
+
+	fields := map[string]string{"title": title, "body": withMarker(marker, body)}
+	result := &Issue{}
+	if issue == nil {
+		path := fmt.Sprintf("/repos/%s/issues", client.Repository)
+		err = client.do(http.MethodPost, path, fields, result)
+	} else {
+		path := fmt.Sprintf("/repos/%s/issues/%d", client.Repository, issue.Number)
+		err = client.do(http.MethodPatch, path, fields, result)
+	}
+
+	return result.Number, err
+}
+
+// CloseIssue updates the body of the first open issue whose body contains 'marker'
+// and closes it. It returns the number of the closed issue or 0 if there is no such
+// issue.
+func (client *Client) CloseIssue(marker, body string) (int, error) {
+	issue, err := client.FindIssue(marker)
+	if err != nil || issue == nil {
+		return 0, err
+	}
+
+	fields := map[string]string{"body": withMarker(marker, body), "state": "closed"}
+	path := fmt.Sprintf("/repos/%s/issues/%d", client.Repository, issue.Number)
+	return issue.Number, client.do(http.MethodPatch, path, fields, nil)
+}
+
+// do sends a request with the JSON encoded 'payload' to the given path and decodes the
+// JSON response in 'result'. 'payload' and 'result' are ignored if nil.
+func (client *Client) do(method, path string, payload, result interface{}) error {
+	const errFmt = "unable to %s %s"
+
+	var requestBody io.Reader
+	if payload != nil {
+		content, err := json.Marshal(payload)
+		if err != nil {
+			return errors.Wrapf(err, errFmt, method, path)
+		}
+
+		requestBody = bytes.NewReader(content)
+	}
+
+	request, err := http.NewRequest(method, strings.TrimSuffix(client.APIURL, "/")+path, requestBody)
+	if err != nil {
+		return errors.Wrapf(err, errFmt, method, path)
+	}
+
+	request.Header.Set("Accept", "application/vnd.github.v3+json")
+	request.Header.Set("Authorization", "token "+client.Token)
+	if payload != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := client.HTTPClient.Do(request)
+	if err != nil {
+		return errors.Wrapf(err, errFmt, method, path)
+	}
+
+	defer response.Body.Close()
+	content, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return errors.Wrapf(err, errFmt, method, path)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err := fmt.Errorf("%s: %s", response.Status, strings.TrimSpace(string(content)))
+		return errors.Wrapf(err, errFmt, method, path)
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(content, result); err != nil {
+			return errors.Wrapf(err, errFmt, method, path)
+		}
+	}
+
+	return nil
+}
+
+// withMarker prepends 'marker' to 'body' if it doesn't contain it.
+func withMarker(marker, body string) string {
+	if strings.Contains(body, marker) {
+		return body
+	}
+
+	return marker + "\n" + body
+}
@@ -0,0 +1,247 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// baselineVersion is the version of the baseline file format.
+const baselineVersion = 1
+
+// Baseline declares the known issues of a project that are suppressed in its reports,
+// e.g. to adopt the checks in an existing project without fixing all of its issues
+// first.
+type Baseline struct {
+	Version int             `json:"version"`
+	Issues  []BaselineIssue `json:"issues"`
+}
+
+// BaselineIssue declares a single known issue in a baseline. ID is one of the rule ids
+// used in the SARIF reports, e.g. 'missing-translation'. Project is only set for the
+// issues of merged reports.
+type BaselineIssue struct {
+	ID      string `json:"id"`
+	Project string `json:"project,omitempty"`
+	Name    string `json:"name"`
+	Locale  string `json:"locale"`
+}
+
+// NewBaseline creates a baseline containing all the issues in the given report.
+func NewBaseline(report *Report) *Baseline {
+	issues := make([]BaselineIssue, 0)
+	for _, str := range report.Strings {
+		issues = append(issues, baselineIssues(str)...)
+	}
+
+	sortBaselineIssues(issues)
+	return &Baseline{Version: baselineVersion, Issues: issues}
+}
+
+// LoadBaseline reads the baseline file at 'file'.
+func LoadBaseline(file string) (*Baseline, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read file at %s", file)
+	}
+
+	baseline := &Baseline{}
+	if err := json.Unmarshal(content, baseline); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse baseline file at %s", file)
+	}
+
+	if baseline.Version != baselineVersion {
+		const errFmt = "unsupported version %d of baseline file at %s"
+		return nil, fmt.Errorf(errFmt, baseline.Version, file)
+	}
+
+	return baseline, nil
+}
+
+// Save writes the baseline to the file at 'file'.
+func (baseline *Baseline) Save(file string) error {
+	content, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal baseline as JSON")
+	}
+
+	if err := ioutil.WriteFile(file, append(content, '\n'), 0644); err != nil {
+		return errors.Wrapf(err, "unable to write file at %s", file)
+	}
+
+	return nil
+}
+
+// ApplyBaseline removes the issues in the baseline from the string resources in the
+// report. The resources left without any issues are removed from the report. The known
+// missing translations are counted as translated in the coverage of the report, so they
+// don't fail the coverage gates either. It returns a baseline containing only the
+// issues that still apply, i.e. the given baseline pruned of the fixed issues.
+func (report *Report) ApplyBaseline(baseline *Baseline) *Baseline {
+	known := baseline.issueSet()
+	applied := make([]BaselineIssue, 0)
+	isKnown := func(id string, str StringResource, locale string) bool {
+		issue := BaselineIssue{ID: id, Project: str.Project, Name: str.Name, Locale: locale}
+		if known[issue] {
+			applied = append(applied, issue)
+			return true
+		}
+
+		return false
+	}
+
+	strs := make([]StringResource, 0, len(report.Strings))
+	for _, str := range report.Strings {
+		str.MissingLocales = filterLocales(str.MissingLocales, func(locale string) bool {
+			return isKnown(ruleMissing, str, locale)
+		})
+
+		str.OutdatedLocales = filterLocales(str.OutdatedLocales, func(locale string) bool {
+			return isKnown(ruleOutdated, str, locale)
+		})
+
+		str.StaleLocales = filterLocales(str.StaleLocales, func(locale string) bool {
+			return isKnown(ruleStale, str, locale)
+		})
+
+		str.IdenticalLocales = filterLocales(str.IdenticalLocales, func(locale string) bool {
+			return isKnown(ruleIdentical, str, locale)
+		})
+
+		for _, locale := range incompleteLocales(str) {
+			if isKnown(ruleIncomplete, str, locale) {
+				delete(str.MissingQuantities, locale)
+				delete(str.ItemCountMismatch, locale)
+			}
+		}
+
+		for locale := range str.PlaceholderMismatches {
+			if isKnown(rulePlaceholder, str, locale) {
+				delete(str.PlaceholderMismatches, locale)
+			}
+		}
+
+		issueCount := len(str.PlaceholderMismatches) + len(str.IdenticalLocales)
+		if str.IsStale() || str.hasTranslationIssues() || issueCount > 0 {
+			strs = append(strs, str)
+		}
+	}
+
+	report.Strings = strs
+	report.excludeKnownMissing(applied)
+	sortBaselineIssues(applied)
+	return &Baseline{Version: baselineVersion, Issues: applied}
+}
+
+// excludeKnownMissing counts the missing translations among the given known issues as
+// translated in the coverage of the report and of its projects.
+func (report *Report) excludeKnownMissing(known []BaselineIssue) {
+	missing := make(map[string]map[string]int) // project => locale => count
+	for _, issue := range known {
+		if issue.ID != ruleMissing {
+			continue
+		}
+
+		if _, ok := missing[issue.Project]; !ok {
+			missing[issue.Project] = make(map[string]int)
+		}
+
+		missing[issue.Project][issue.Locale]++
+	}
+
+	if len(report.Projects) == 0 {
+		report.Coverage = report.Coverage.withTranslated(missing[""])
+		return
+	}
+
+	for i, project := range report.Projects {
+		report.Projects[i].Coverage = project.Coverage.withTranslated(missing[project.Project])
+	}
+
+	report.Coverage = mergeCoverage(report.Projects)
+}
+
+// issueSet returns the set of the issues in the baseline.
+func (baseline *Baseline) issueSet() map[BaselineIssue]bool {
+	known := make(map[BaselineIssue]bool, len(baseline.Issues))
+	for _, issue := range baseline.Issues {
+		known[issue] = true
+	}
+
+	return known
+}
+
+// baselineIssues returns the baseline issues for the findings of the given string
+// resource.
+func baselineIssues(str StringResource) []BaselineIssue {
+	issues := make([]BaselineIssue, 0)
+	add := func(id string, locales []string) {
+		for _, locale := range locales {
+			issues = append(issues, BaselineIssue{ID: id, Project: str.Project, Name: str.Name, Locale: locale})
+		}
+	}
+
+	add(ruleMissing, str.MissingLocales)
+	add(ruleIncomplete, incompleteLocales(str))
+	add(ruleOutdated, str.OutdatedLocales)
+	add(ruleStale, str.StaleLocales)
+	add(ruleIdentical, str.IdenticalLocales)
+	placeholderLocales := make([]string, 0, len(str.PlaceholderMismatches))
+	for locale := range str.PlaceholderMismatches {
+		placeholderLocales = append(placeholderLocales, locale)
+	}
+
+	add(rulePlaceholder, placeholderLocales)
+	return issues
+}
+
+// incompleteLocales returns the locales with missing 'plurals' quantities or mismatching
+// 'string-array' item counts of the given string resource.
+func incompleteLocales(str StringResource) []string {
+	locales := make([]string, 0, len(str.MissingQuantities)+len(str.ItemCountMismatch))
+	for locale := range str.MissingQuantities {
+		locales = append(locales, locale)
+	}
+
+	for locale := range str.ItemCountMismatch {
+		if _, ok := str.MissingQuantities[locale]; !ok {
+			locales = append(locales, locale)
+		}
+	}
+
+	return locales
+}
+
+// filterLocales returns the locales for which 'exclude' returns false.
+func filterLocales(locales []string, exclude func(locale string) bool) []string {
+	filtered := make([]string, 0, len(locales))
+	for _, locale := range locales {
+		if !exclude(locale) {
+			filtered = append(filtered, locale)
+		}
+	}
+
+	return filtered
+}
+
+// sortBaselineIssues sorts the given issues by their projects, names, ids and locales.
+func sortBaselineIssues(issues []BaselineIssue) {
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Project != issues[j].Project {
+			return issues[i].Project < issues[j].Project
+		}
+
+		if issues[i].Name != issues[j].Name {
+			return issues[i].Name < issues[j].Name
+		}
+
+		if issues[i].ID != issues[j].ID {
+			return issues[i].ID < issues[j].ID
+		}
+
+		return issues[i].Locale < issues[j].Locale
+	})
+}
@@ -0,0 +1,213 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyBaseline(t *testing.T) {
+	strs := []StringResource{
+		{
+			Name:                  "title",
+			MissingLocales:        []string{"de", "fr"},
+			OutdatedLocales:       []string{"de"},
+			PlaceholderMismatches: map[string][]string{"de": {"missing %1$s"}},
+		},
+		{
+			Name:              "items",
+			MissingLocales:    []string{},
+			OutdatedLocales:   []string{},
+			MissingQuantities: map[string][]string{"ru": {"few", "many"}},
+			ItemCountMismatch: map[string]int{},
+		},
+		{
+			Name:            "removed",
+			MissingLocales:  []string{},
+			OutdatedLocales: []string{},
+			StaleLocales:    []string{"de"},
+		},
+	}
+
+	coverage := Coverage{
+		Translated: 7,
+		Missing:    2,
+		Percent:    77.78,
+		Locales: []LocaleCoverage{
+			{Locale: "de", Translated: 2, Missing: 1, Percent: 66.67},
+			{Locale: "fr", Translated: 2, Missing: 1, Percent: 66.67},
+			{Locale: "ru", Translated: 3, Missing: 0, Percent: 100},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		known        []BaselineIssue
+		wantIssues   []BaselineIssue
+		wantApplied  []BaselineIssue
+		wantCoverage Coverage
+	}{
+		{
+			name: "empty baseline",
+			wantIssues: []BaselineIssue{
+				{ID: ruleIncomplete, Name: "items", Locale: "ru"},
+				{ID: ruleStale, Name: "removed", Locale: "de"},
+				{ID: ruleMissing, Name: "title", Locale: "de"},
+				{ID: ruleMissing, Name: "title", Locale: "fr"},
+				{ID: ruleOutdated, Name: "title", Locale: "de"},
+				{ID: rulePlaceholder, Name: "title", Locale: "de"},
+			},
+			wantApplied:  []BaselineIssue{},
+			wantCoverage: coverage,
+		},
+		{
+			name: "some known issues",
+			known: []BaselineIssue{
+				{ID: ruleMissing, Name: "title", Locale: "de"},
+				{ID: rulePlaceholder, Name: "title", Locale: "de"},
+				{ID: ruleIncomplete, Name: "items", Locale: "ru"},
+			},
+			wantIssues: []BaselineIssue{
+				{ID: ruleStale, Name: "removed", Locale: "de"},
+				{ID: ruleMissing, Name: "title", Locale: "fr"},
+				{ID: ruleOutdated, Name: "title", Locale: "de"},
+			},
+			wantApplied: []BaselineIssue{
+				{ID: ruleIncomplete, Name: "items", Locale: "ru"},
+				{ID: ruleMissing, Name: "title", Locale: "de"},
+				{ID: rulePlaceholder, Name: "title", Locale: "de"},
+			},
+			wantCoverage: Coverage{
+				Translated: 8,
+				Missing:    1,
+				Percent:    88.89,
+				Locales: []LocaleCoverage{
+					{Locale: "de", Translated: 3, Missing: 0, Percent: 100},
+					{Locale: "fr", Translated: 2, Missing: 1, Percent: 66.67},
+					{Locale: "ru", Translated: 3, Missing: 0, Percent: 100},
+				},
+			},
+		},
+		{
+			name: "fixed issues",
+			known: []BaselineIssue{
+				{ID: ruleStale, Name: "removed", Locale: "de"},
+				{ID: ruleMissing, Name: "title", Locale: "ru"},
+				{ID: ruleOutdated, Name: "body", Locale: "de"},
+			},
+			wantIssues: []BaselineIssue{
+				{ID: ruleIncomplete, Name: "items", Locale: "ru"},
+				{ID: ruleMissing, Name: "title", Locale: "de"},
+				{ID: ruleMissing, Name: "title", Locale: "fr"},
+				{ID: ruleOutdated, Name: "title", Locale: "de"},
+				{ID: rulePlaceholder, Name: "title", Locale: "de"},
+			},
+			wantApplied: []BaselineIssue{
+				{ID: ruleStale, Name: "removed", Locale: "de"},
+			},
+			wantCoverage: coverage,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report := &Report{Strings: copyStringResources(strs), Coverage: coverage}
+			applied := report.ApplyBaseline(&Baseline{Version: baselineVersion, Issues: test.known})
+			if !reflect.DeepEqual(applied.Issues, test.wantApplied) {
+				t.Errorf("ApplyBaseline() applied = %v, want %v", applied.Issues, test.wantApplied)
+			}
+
+			if issues := NewBaseline(report).Issues; !reflect.DeepEqual(issues, test.wantIssues) {
+				t.Errorf("ApplyBaseline() left issues %v, want %v", issues, test.wantIssues)
+			}
+
+			if !reflect.DeepEqual(report.Coverage, test.wantCoverage) {
+				t.Errorf("ApplyBaseline() coverage = %+v, want %+v", report.Coverage, test.wantCoverage)
+			}
+		})
+	}
+}
+
+func TestApplyBaselineToMergedReport(t *testing.T) {
+	reports := []*Report{
+		{
+			Strings: []StringResource{{Name: "title", MissingLocales: []string{"de"}}},
+			Coverage: Coverage{Locales: []LocaleCoverage{
+				{Locale: "de", Translated: 1, Missing: 1, Percent: 50},
+			}},
+		},
+		{
+			Strings: []StringResource{{Name: "title", MissingLocales: []string{"de"}}},
+			Coverage: Coverage{Locales: []LocaleCoverage{
+				{Locale: "de", Translated: 0, Missing: 1, Percent: 0},
+			}},
+		},
+	}
+
+	report := MergeReports([]string{"one", "two"}, reports)
+	report.ApplyBaseline(&Baseline{
+		Version: baselineVersion,
+		Issues:  []BaselineIssue{{ID: ruleMissing, Project: "two", Name: "title", Locale: "de"}},
+	})
+
+	wantProjects := []ProjectCoverage{
+		{Project: "one", Coverage: Coverage{Translated: 1, Missing: 1, Percent: 50, Locales: []LocaleCoverage{
+			{Locale: "de", Translated: 1, Missing: 1, Percent: 50},
+		}}},
+		{Project: "two", Coverage: Coverage{Translated: 1, Missing: 0, Percent: 100, Locales: []LocaleCoverage{
+			{Locale: "de", Translated: 1, Missing: 0, Percent: 100},
+		}}},
+	}
+
+	if !reflect.DeepEqual(report.Projects, wantProjects) {
+		t.Errorf("ApplyBaseline() projects = %+v, want %+v", report.Projects, wantProjects)
+	}
+
+	wantCoverage := Coverage{Translated: 2, Missing: 1, Percent: 66.67, Locales: []LocaleCoverage{
+		{Locale: "de", Translated: 2, Missing: 1, Percent: 66.67},
+	}}
+
+	if !reflect.DeepEqual(report.Coverage, wantCoverage) {
+		t.Errorf("ApplyBaseline() coverage = %+v, want %+v", report.Coverage, wantCoverage)
+	}
+}
+
+func TestApplyBaselineRemovesResolvedStrings(t *testing.T) {
+	report := &Report{Strings: []StringResource{
+		{Name: "title", MissingLocales: []string{"de"}, OutdatedLocales: []string{}},
+		{Name: "body", MissingLocales: []string{"de"}, OutdatedLocales: []string{}},
+	}}
+
+	report.ApplyBaseline(&Baseline{Version: baselineVersion, Issues: []BaselineIssue{
+		{ID: ruleMissing, Name: "title", Locale: "de"},
+	}})
+
+	if len(report.Strings) != 1 || report.Strings[0].Name != "body" {
+		t.Errorf("ApplyBaseline() left strings %+v, want only 'body'", report.Strings)
+	}
+}
+
+// copyStringResources returns a deep copy of the locales and the maps of the given
+// string resources, since ApplyBaseline modifies the maps in place.
+func copyStringResources(strs []StringResource) []StringResource {
+	copied := make([]StringResource, 0, len(strs))
+	for _, str := range strs {
+		str.MissingLocales = append([]string{}, str.MissingLocales...)
+		str.OutdatedLocales = append([]string{}, str.OutdatedLocales...)
+		str.StaleLocales = append([]string{}, str.StaleLocales...)
+		placeholderMismatches := make(map[string][]string, len(str.PlaceholderMismatches))
+		for locale, mismatches := range str.PlaceholderMismatches {
+			placeholderMismatches[locale] = mismatches
+		}
+
+		missingQuantities := make(map[string][]string, len(str.MissingQuantities))
+		for locale, quantities := range str.MissingQuantities {
+			missingQuantities[locale] = quantities
+		}
+
+		str.PlaceholderMismatches = placeholderMismatches
+		str.MissingQuantities = missingQuantities
+		copied = append(copied, str)
+	}
+
+	return copied
+}
@@ -0,0 +1,112 @@
+package report
+
+import (
+	"math"
+
+	"github.com/ashutoshgngwr/android-translations/pkg/resources"
+)
+
+// Coverage declares the translation coverage of a project.
+type Coverage struct {
+	Translated int              `json:"translated"`
+	Missing    int              `json:"missing"`
+	Percent    float64          `json:"coverage_percent"`
+	Locales    []LocaleCoverage `json:"locales"`
+}
+
+// LocaleCoverage declares the translation coverage of a single locale.
+type LocaleCoverage struct {
+	Locale     string  `json:"locale"`
+	Name       string  `json:"name,omitempty"`
+	Translated int     `json:"translated"`
+	Missing    int     `json:"missing"`
+	Percent    float64 `json:"coverage_percent"`
+}
+
+// LocaleCoverage returns the coverage of the given locale and true if the locale has
+// translations. It returns false otherwise.
+func (coverage Coverage) LocaleCoverage(locale string) (LocaleCoverage, bool) {
+	for _, localeCoverage := range coverage.Locales {
+		if localeCoverage.Locale == locale {
+			return localeCoverage, true
+		}
+	}
+
+	return LocaleCoverage{}, false
+}
+
+// withLocaleNames returns a copy of the coverage with the English names of the
+// locales. See resources.LocaleDisplayName.
+func (coverage Coverage) withLocaleNames() Coverage {
+	locales := make([]LocaleCoverage, 0, len(coverage.Locales))
+	for _, localeCoverage := range coverage.Locales {
+		localeCoverage.Name = resources.LocaleDisplayName(localeCoverage.Locale)
+		locales = append(locales, localeCoverage)
+	}
+
+	coverage.Locales = locales
+	return coverage
+}
+
+// withTranslated returns a copy of the coverage where the given counts of missing
+// translations, keyed by their locales, are counted as translated.
+func (coverage Coverage) withTranslated(counts map[string]int) Coverage {
+	result := Coverage{Locales: make([]LocaleCoverage, 0, len(coverage.Locales))}
+	for _, localeCoverage := range coverage.Locales {
+		count := counts[localeCoverage.Locale]
+		if count > localeCoverage.Missing {
+			count = localeCoverage.Missing
+		}
+
+		localeCoverage.Translated += count
+		localeCoverage.Missing -= count
+		localeCoverage.Percent = percent(localeCoverage.Translated, localeCoverage.Translated+localeCoverage.Missing)
+		result.Translated += localeCoverage.Translated
+		result.Missing += localeCoverage.Missing
+		result.Locales = append(result.Locales, localeCoverage)
+	}
+
+	result.Percent = percent(result.Translated, result.Translated+result.Missing)
+	return result
+}
+
+// computeCoverage computes the coverage of the given locales where 'total' is the count
+// of the resources in the default locale that require translations and 'strs' are the
+// resources with translation issues. A translation is considered missing if it doesn't
+// exist or is empty, e.g. a stub. The overall coverage is the ratio of all translations
+// to all expected translations.
+func computeCoverage(total int, locales []string, strs []StringResource) Coverage {
+	missing := make(map[string]int, len(locales))
+	for _, str := range strs {
+		for _, locale := range str.MissingLocales {
+			missing[locale]++
+		}
+	}
+
+	coverage := Coverage{Locales: make([]LocaleCoverage, 0, len(locales))}
+	for _, locale := range locales {
+		localeCoverage := LocaleCoverage{
+			Locale:     locale,
+			Translated: total - missing[locale],
+			Missing:    missing[locale],
+			Percent:    percent(total-missing[locale], total),
+		}
+
+		coverage.Translated += localeCoverage.Translated
+		coverage.Missing += localeCoverage.Missing
+		coverage.Locales = append(coverage.Locales, localeCoverage)
+	}
+
+	coverage.Percent = percent(coverage.Translated, coverage.Translated+coverage.Missing)
+	return coverage
+}
+
+// percent returns 'part' as the percentage of 'total' rounded to two decimal places.
+// If 'total' is zero, it returns 100.
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+
+	return math.Round(10000*float64(part)/float64(total)) / 100
+}
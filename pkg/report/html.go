@@ -0,0 +1,255 @@
+package report
+
+import (
+	"bytes"
+	"html/template"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// htmlTemplate is the template for rendering reports as standalone HTML documents. The
+// styles and the scripts for sorting and filtering the tables are inlined.
+var htmlTemplate = template.Must(template.New("html").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 1200px; padding: 0 1em; color: #24292e; }
+table { border-collapse: collapse; width: 100%; margin: 1em 0; }
+th, td { border: 1px solid #d1d5da; padding: 6px 10px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+th.sortable { cursor: pointer; user-select: none; }
+th.sortable::after { content: " \2195"; color: #959da5; }
+th.asc::after { content: " \2191"; color: #24292e; }
+th.desc::after { content: " \2193"; color: #24292e; }
+code { background: #f6f8fa; padding: 1px 4px; border-radius: 3px; }
+input.filter { width: 100%; padding: 6px 10px; box-sizing: border-box; font-size: 1em; }
+.bar { background: #e1e4e8; border-radius: 3px; height: 12px; min-width: 120px; }
+.bar > div { background: #2ea44f; border-radius: 3px; height: 100%; }
+.muted { color: #6a737d; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+{{- if .SinceRef }}
+<p class="muted">Only the strings added or changed since <code>{{ .SinceRef }}</code> are included.</p>
+{{- end }}
+<h2>Coverage</h2>
+<p>Overall coverage is <strong>{{ printf "%.2f" .Coverage.Percent }}%</strong> with {{ .Coverage.Translated }} translations present and {{ .Coverage.Missing }} missing.</p>
+{{- if .Projects }}
+<table class="sortable">
+<thead><tr><th class="sortable">Project</th><th class="sortable">Translated</th><th class="sortable">Missing</th><th class="sortable">Coverage</th><th></th></tr></thead>
+<tbody>
+{{- range .Projects }}
+<tr><td>{{ .Project }}</td><td>{{ .Coverage.Translated }}</td><td>{{ .Coverage.Missing }}</td><td>{{ printf "%.2f" .Coverage.Percent }}%</td><td><div class="bar"><div style="width: {{ printf "%.2f" .Coverage.Percent }}%"></div></div></td></tr>
+{{- end }}
+</tbody>
+</table>
+{{- end }}
+{{- if .Coverage.Locales }}
+<table class="sortable">
+<thead><tr><th class="sortable">Locale</th>{{ if .LocaleNames }}<th class="sortable">Name</th>{{ end }}<th class="sortable">Translated</th><th class="sortable">Missing</th><th class="sortable">Coverage</th><th></th></tr></thead>
+<tbody>
+{{- range .Coverage.Locales }}
+<tr><td>{{ .Locale }}</td>{{ if $.LocaleNames }}<td>{{ .Name }}</td>{{ end }}<td>{{ .Translated }}</td><td>{{ .Missing }}</td><td>{{ printf "%.2f" .Percent }}%</td><td><div class="bar"><div style="width: {{ printf "%.2f" .Percent }}%"></div></div></td></tr>
+{{- end }}
+</tbody>
+</table>
+{{- end }}
+{{- if .MissingOn }}
+<h2>Translation Issues</h2>
+{{- if .Strings }}
+<input class="filter" type="search" placeholder="Filter by name, project, module, value or locale" data-table="strings">
+<table class="sortable" id="strings">
+<thead><tr>{{ if .Projects }}<th class="sortable">Project</th>{{ end }}<th class="sortable">Name</th><th class="sortable">Module</th><th class="sortable">Type</th><th class="sortable">Default Value</th><th class="sortable">Missing Locales</th><th class="sortable">Incomplete Locales</th>{{ if .OutdatedOn }}<th class="sortable">Potentially Outdated Locales</th>{{ end }}</tr></thead>
+<tbody>
+{{- range .Strings }}
+<tr>{{ if $.Projects }}<td>{{ .Project }}</td>{{ end }}<td>{{ if .Link }}<a href="{{ .Link }}"><code>{{ .Name }}</code></a>{{ else }}<code>{{ .Name }}</code>{{ end }}</td><td>{{ .Module }}</td><td>{{ .Type }}</td><td>{{ .Value }}</td><td>{{ .Missing }}</td><td>{{ .Incomplete }}</td>{{ if $.OutdatedOn }}<td>{{ .Outdated }}</td>{{ end }}</tr>
+{{- end }}
+</tbody>
+</table>
+{{- else }}
+<p>No missing {{- if .OutdatedOn }} or outdated {{- end }} translations found.</p>
+{{- end }}
+{{- if .PlaceholdersOn }}
+<h2>Placeholder Mismatches</h2>
+{{- if .Placeholders }}
+<table class="sortable">
+<thead><tr><th class="sortable">Name</th><th class="sortable">Locale</th><th>Mismatches</th></tr></thead>
+<tbody>
+{{- range .Placeholders }}
+<tr><td><code>{{ .Name }}</code></td><td>{{ .Locale }}</td><td>{{ range $i, $m := .Mismatches }}{{ if $i }}, {{ end }}<code>{{ $m }}</code>{{ end }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+{{- else }}
+<p>No placeholder mismatches found.</p>
+{{- end }}
+{{- end }}
+{{- if .IdenticalOn }}
+<h2>Suspicious Translations</h2>
+{{- if .Identical }}
+<p>The following translations are identical to their default strings.</p>
+<table class="sortable">
+<thead><tr><th class="sortable">Name</th><th class="sortable">Default Value</th><th class="sortable">Identical Locales</th></tr></thead>
+<tbody>
+{{- range .Identical }}
+<tr><td><code>{{ .Name }}</code></td><td>{{ .Value }}</td><td>{{ range $i, $locale := .IdenticalLocales }}{{ if $i }}, {{ end }}{{ $locale }}{{ end }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+{{- else }}
+<p>No translations identical to the default strings found.</p>
+{{- end }}
+{{- end }}
+{{- if .StaleOn }}
+<h2>Stale Translations</h2>
+{{- if .Stale }}
+<table class="sortable">
+<thead><tr><th class="sortable">Locale</th><th>Stale Strings</th></tr></thead>
+<tbody>
+{{- range .Stale }}
+<tr><td>{{ .Locale }}</td><td>{{ range $i, $name := .Names }}{{ if $i }}, {{ end }}<code>{{ $name }}</code>{{ end }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+{{- else }}
+<p>No stale translations found.</p>
+{{- end }}
+{{- end }}
+{{- end }}
+{{- if .IgnoredOn }}
+<h2>Ignored</h2>
+<p>Paths: {{ range $i, $path := .Ignored.Paths }}{{ if $i }}, {{ end }}<code>{{ $path }}</code>{{ else }}<span class="muted">none</span>{{ end }}</p>
+<p>Strings: {{ range $i, $name := .Ignored.Strings }}{{ if $i }}, {{ end }}<code>{{ $name }}</code>{{ else }}<span class="muted">none</span>{{ end }}</p>
+{{- end }}
+<p class="muted">Generated using <a href="https://github.com/ashutoshgngwr/android-translations">Android Translations</a>.</p>
+<script>
+document.querySelectorAll("table.sortable").forEach(function (table) {
+  table.querySelectorAll("th.sortable").forEach(function (th) {
+    th.addEventListener("click", function () {
+      var index = Array.prototype.indexOf.call(th.parentNode.children, th);
+      var asc = !th.classList.contains("asc");
+      table.querySelectorAll("th").forEach(function (other) { other.classList.remove("asc", "desc"); });
+      th.classList.add(asc ? "asc" : "desc");
+      var tbody = table.tBodies[0];
+      var rows = Array.prototype.slice.call(tbody.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[index].textContent.trim(), y = b.cells[index].textContent.trim();
+        var nx = parseFloat(x), ny = parseFloat(y);
+        var result = !isNaN(nx) && !isNaN(ny) ? nx - ny : x.localeCompare(y);
+        return asc ? result : -result;
+      });
+      rows.forEach(function (row) { tbody.appendChild(row); });
+    });
+  });
+});
+document.querySelectorAll("input.filter").forEach(function (input) {
+  input.addEventListener("input", function () {
+    var query = input.value.toLowerCase();
+    var table = document.getElementById(input.getAttribute("data-table"));
+    Array.prototype.forEach.call(table.tBodies[0].rows, function (row) {
+      row.style.display = row.textContent.toLowerCase().indexOf(query) >= 0 ? "" : "none";
+    });
+  });
+});
+</script>
+</body>
+</html>
+`))
+
+// htmlString declares the data of a single row in the HTML table of the string
+// resources.
+type htmlString struct {
+	Project    string
+	Name       string
+	Link       string
+	Module     string
+	Type       string
+	Value      string
+	Missing    string
+	Incomplete string
+	Outdated   string
+}
+
+// htmlPlaceholderMismatch declares the data of a single row in the HTML table of the
+// placeholder mismatches.
+type htmlPlaceholderMismatch struct {
+	Name       string
+	Locale     string
+	Mismatches []string
+}
+
+// RenderHTML renders the given report as a standalone HTML document. The coverage of
+// each locale is always included regardless of the mode, and the translation issues
+// are included with ModeMissing or ModeAll. If 'opts.SourceBaseURL' is set, the names
+// of the strings link to their declarations.
+func RenderHTML(report *Report, opts RenderOptions) (string, error) {
+	strs := make([]htmlString, 0, len(report.Strings))
+	placeholders := make([]htmlPlaceholderMismatch, 0)
+	identical := make([]StringResource, 0)
+	for _, item := range report.Strings {
+		if len(item.IdenticalLocales) > 0 {
+			identical = append(identical, item)
+		}
+
+		locales := make([]string, 0, len(item.PlaceholderMismatches))
+		for locale := range item.PlaceholderMismatches {
+			locales = append(locales, locale)
+		}
+
+		sort.Strings(locales)
+		for _, locale := range locales {
+			placeholders = append(placeholders, htmlPlaceholderMismatch{
+				Name:       item.Name,
+				Locale:     locale,
+				Mismatches: item.PlaceholderMismatches[locale],
+			})
+		}
+
+		if item.IsStale() || !item.hasTranslationIssues() {
+			continue
+		}
+
+		strs = append(strs, htmlString{
+			Project:    item.Project,
+			Name:       item.Name,
+			Link:       sourceLink(opts.SourceBaseURL, item),
+			Module:     item.Module,
+			Type:       item.Type,
+			Value:      item.Value,
+			Missing:    item.MissingLocalesString(),
+			Incomplete: item.IncompleteLocalesString(),
+			Outdated:   item.OutdatedLocalesString(),
+		})
+	}
+
+	var content bytes.Buffer
+	err := htmlTemplate.Execute(&content, map[string]interface{}{
+		"Title":          opts.Title,
+		"SinceRef":       report.Options.SinceRef,
+		"MissingOn":      opts.Mode.includesMissing(),
+		"OutdatedOn":     report.Options.OutdatedLocales,
+		"Strings":        strs,
+		"PlaceholdersOn": report.Options.CheckPlaceholders,
+		"Placeholders":   placeholders,
+		"IdenticalOn":    report.Options.CheckIdentical,
+		"Identical":      identical,
+		"StaleOn":        report.Options.CheckStale,
+		"Stale":          report.staleLocales(),
+		"IgnoredOn":      report.Options.ListIgnored,
+		"Ignored":        report.Ignored,
+		"Projects":       report.Projects,
+		"Coverage":       opts.coverage(report),
+		"LocaleNames":    opts.LocaleNames,
+	})
+
+	if err != nil {
+		return "", errors.Wrap(err, "unable to render data as HTML")
+	}
+
+	return content.String(), nil
+}
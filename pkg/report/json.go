@@ -0,0 +1,43 @@
+package report
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// RenderJSON marshals the given report as JSON. It renders an object containing the
+// version of its structure as 'schema_version' field and the metadata of the run as
+// 'metadata' field. With ModeMissing, the object contains the array of string resources
+// as 'strings' field. With ModeCoverage, it contains the coverage object as 'coverage'
+// field and the coverage of each project as 'projects' field if the report was merged
+// from several projects. ModeAll includes all of them. The object contains the ignored
+// items as 'ignored' field if they were listed.
+func RenderJSON(report *Report, opts RenderOptions) (string, error) {
+	v := map[string]interface{}{
+		"schema_version": SchemaVersion,
+		"metadata":       report.Metadata(),
+	}
+
+	if opts.Mode.includesMissing() {
+		v["strings"] = report.Strings
+	}
+
+	if opts.Mode.includesCoverage() {
+		v["coverage"] = opts.coverage(report)
+		if len(report.Projects) > 0 {
+			v["projects"] = report.Projects
+		}
+	}
+
+	if report.Options.ListIgnored {
+		v["ignored"] = report.Ignored
+	}
+
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal content as JSON")
+	}
+
+	return string(content), nil
+}
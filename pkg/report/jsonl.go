@@ -0,0 +1,227 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SchemaVersion is the version of the structure of the JSON objects in the JSON and
+// JSON Lines reports. It is incremented whenever a field is removed or its meaning
+// changes. Adding fields doesn't change the version.
+const SchemaVersion = 1
+
+// Metadata declares the information about the run that produced a report.
+type Metadata struct {
+	GeneratedAt     time.Time `json:"generated_at"`
+	ProjectDirs     []string  `json:"project_dirs"`
+	DefaultLanguage string    `json:"default_language"`
+	Locales         []string  `json:"locales"`
+}
+
+// Finding declares a single issue of a string resource in a single locale. Rule is
+// one of the rule ids used in the SARIF reports, e.g. 'missing-translation'. Details
+// contains the missing quantities of incomplete 'plurals' and the descriptions of
+// placeholder mismatches.
+type Finding struct {
+	Rule    string   `json:"rule"`
+	Level   string   `json:"level"`
+	Project string   `json:"project,omitempty"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Module  string   `json:"module"`
+	Locale  string   `json:"locale"`
+	File    string   `json:"file"`
+	Line    int      `json:"line"`
+	Details []string `json:"details,omitempty"`
+}
+
+// metadataLine declares the data structure of the metadata line in JSON Lines reports.
+type metadataLine struct {
+	Record        string `json:"record"`
+	SchemaVersion int    `json:"schema_version"`
+	Metadata
+}
+
+// findingLine declares the data structure of the finding lines in JSON Lines reports.
+type findingLine struct {
+	Record        string `json:"record"`
+	SchemaVersion int    `json:"schema_version"`
+	Finding
+}
+
+// coverageLine declares the data structure of the coverage lines in JSON Lines
+// reports.
+type coverageLine struct {
+	Record        string `json:"record"`
+	SchemaVersion int    `json:"schema_version"`
+	LocaleCoverage
+}
+
+// ignoredLine declares the data structure of the ignored items lines in JSON Lines
+// reports.
+type ignoredLine struct {
+	Record        string `json:"record"`
+	SchemaVersion int    `json:"schema_version"`
+	IgnoredItems
+}
+
+// Metadata returns the metadata of the run that produced the report.
+func (report *Report) Metadata() Metadata {
+	projectDirs := make([]string, 0, len(report.Projects))
+	for _, project := range report.Projects {
+		projectDirs = append(projectDirs, project.Project)
+	}
+
+	if len(projectDirs) == 0 {
+		projectDirs = append(projectDirs, report.ProjectDir)
+	}
+
+	return Metadata{
+		GeneratedAt:     report.GeneratedAt,
+		ProjectDirs:     projectDirs,
+		DefaultLanguage: report.DefaultLanguage,
+		Locales:         report.Locales,
+	}
+}
+
+// JSONLinesWriter writes reports as JSON Lines, i.e. one JSON object per line, while
+// their projects are scanned. Each object has a 'record' field that is one of
+// 'metadata', 'ignored', 'finding' or 'coverage'. Its Started and Found methods are
+// meant to be used as the callbacks of the same name in Options, so a 'metadata' line is
+// written for each project as soon as it is known, followed by an 'ignored' line if the
+// ignored items are listed, and a 'finding' line for each issue as soon as it is found
+// with ModeMissing or ModeAll. Finish writes a 'coverage' line
+// for each locale of the complete report with ModeCoverage or ModeAll.
+type JSONLinesWriter struct {
+	encoder *json.Encoder
+	opts    RenderOptions
+	known   map[BaselineIssue]bool
+	merged  bool
+	project string
+	err     error
+}
+
+// NewJSONLinesWriter creates a JSONLinesWriter that writes to 'w'. The issues listed in
+// 'baseline', if not nil, are skipped. If 'merged' is true, the findings are labelled
+// with their projects like the strings of the reports merged using MergeReports.
+func NewJSONLinesWriter(w io.Writer, opts RenderOptions, baseline *Baseline, merged bool) *JSONLinesWriter {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	writer := &JSONLinesWriter{encoder: encoder, opts: opts, merged: merged}
+	if baseline != nil {
+		writer.known = baseline.issueSet()
+	}
+
+	return writer
+}
+
+// Started writes the metadata and the ignored items of the given report of a project
+// that is being scanned.
+func (writer *JSONLinesWriter) Started(report *Report) {
+	metadata := report.Metadata()
+	ignored := report.Ignored
+	if writer.merged {
+		writer.project = projectName(report.ProjectDir)
+		metadata.ProjectDirs = []string{writer.project}
+		ignored.Paths = make([]string, 0, len(report.Ignored.Paths))
+		for _, ignoredPath := range report.Ignored.Paths {
+			ignored.Paths = append(ignored.Paths, prefixPath(writer.project, ignoredPath))
+		}
+	}
+
+	writer.write(metadataLine{"metadata", SchemaVersion, metadata})
+	if report.Options.ListIgnored {
+		writer.write(ignoredLine{"ignored", SchemaVersion, ignored})
+	}
+}
+
+// Found writes the findings of the given string resource of the project that is being
+// scanned.
+func (writer *JSONLinesWriter) Found(str StringResource) {
+	if !writer.opts.Mode.includesMissing() {
+		return
+	}
+
+	if writer.merged {
+		str = withProject(writer.project, str)
+	}
+
+	for _, finding := range findings(str) {
+		issue := BaselineIssue{ID: finding.Rule, Project: finding.Project, Name: finding.Name, Locale: finding.Locale}
+		if !writer.known[issue] {
+			writer.write(findingLine{"finding", SchemaVersion, finding})
+		}
+	}
+}
+
+// Finish writes the coverage of the given complete report, i.e. the report of the
+// project or the merged report of all projects. It returns the first error that
+// occurred while writing the report.
+func (writer *JSONLinesWriter) Finish(report *Report) error {
+	if writer.opts.Mode.includesCoverage() {
+		for _, localeCoverage := range writer.opts.coverage(report).Locales {
+			writer.write(coverageLine{"coverage", SchemaVersion, localeCoverage})
+		}
+	}
+
+	return writer.err
+}
+
+// write encodes the given line unless an earlier line failed to encode.
+func (writer *JSONLinesWriter) write(line interface{}) {
+	if writer.err != nil {
+		return
+	}
+
+	if err := writer.encoder.Encode(line); err != nil {
+		writer.err = errors.Wrap(err, "failed to write content as JSON Lines")
+	}
+}
+
+// findings returns a finding for each issue of the given string resource in each
+// locale, sorted by their rules and locales.
+func findings(str StringResource) []Finding {
+	issues := baselineIssues(str)
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].ID != issues[j].ID {
+			return issues[i].ID < issues[j].ID
+		}
+
+		return issues[i].Locale < issues[j].Locale
+	})
+
+	result := make([]Finding, 0, len(issues))
+	for _, issue := range issues {
+		finding := Finding{
+			Rule:    issue.ID,
+			Project: str.Project,
+			Name:    str.Name,
+			Type:    str.Type,
+			Module:  str.Module,
+			Locale:  issue.Locale,
+			File:    str.File,
+			Line:    str.Line,
+		}
+
+		for _, rule := range sarifRules {
+			if rule.ID == issue.ID {
+				finding.Level = rule.DefaultConfiguration.Level
+			}
+		}
+
+		switch issue.ID {
+		case ruleIncomplete:
+			finding.Details = str.MissingQuantities[issue.Locale]
+		case rulePlaceholder:
+			finding.Details = str.PlaceholderMismatches[issue.Locale]
+		}
+
+		result = append(result, finding)
+	}
+
+	return result
+}
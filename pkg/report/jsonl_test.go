@@ -0,0 +1,124 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONLinesWriter(t *testing.T) {
+	var buf bytes.Buffer
+	baseline := &Baseline{
+		Version: baselineVersion,
+		Issues:  []BaselineIssue{{ID: ruleMissing, Project: "apps/one", Name: "title", Locale: "fr"}},
+	}
+
+	writer := NewJSONLinesWriter(&buf, RenderOptions{Mode: ModeAll}, baseline, true)
+	writer.Started(&Report{
+		ProjectDir:      "./apps/one",
+		DefaultLanguage: "en",
+		Locales:         []string{"de", "fr"},
+		Options:         Options{ListIgnored: true},
+		Ignored:         IgnoredItems{Paths: []string{"build"}, Strings: []string{"app_name"}},
+	})
+
+	writer.Found(StringResource{
+		Name:                  "title",
+		Type:                  "string",
+		File:                  "res/values/strings.xml",
+		Line:                  3,
+		MissingLocales:        []string{"fr", "de"},
+		PlaceholderMismatches: map[string][]string{"de": {"missing %1$s"}},
+	})
+
+	err := writer.Finish(&Report{Coverage: Coverage{Locales: []LocaleCoverage{
+		{Locale: "de", Translated: 1, Missing: 1, Percent: 50},
+	}}})
+
+	if err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+
+	want := []map[string]interface{}{
+		{
+			"record":           "metadata",
+			"schema_version":   1.0,
+			"generated_at":     "0001-01-01T00:00:00Z",
+			"project_dirs":     []interface{}{"apps/one"},
+			"default_language": "en",
+			"locales":          []interface{}{"de", "fr"},
+		},
+		{
+			"record":         "ignored",
+			"schema_version": 1.0,
+			"paths":          []interface{}{"apps/one/build"},
+			"strings":        []interface{}{"app_name"},
+		},
+		{
+			"record":         "finding",
+			"schema_version": 1.0,
+			"rule":           ruleMissing,
+			"level":          "warning",
+			"project":        "apps/one",
+			"name":           "title",
+			"type":           "string",
+			"module":         "",
+			"locale":         "de",
+			"file":           "apps/one/res/values/strings.xml",
+			"line":           3.0,
+		},
+		{
+			"record":         "finding",
+			"schema_version": 1.0,
+			"rule":           rulePlaceholder,
+			"level":          "error",
+			"project":        "apps/one",
+			"name":           "title",
+			"type":           "string",
+			"module":         "",
+			"locale":         "de",
+			"file":           "apps/one/res/values/strings.xml",
+			"line":           3.0,
+			"details":        []interface{}{"missing %1$s"},
+		},
+		{
+			"record":           "coverage",
+			"schema_version":   1.0,
+			"locale":           "de",
+			"translated":       1.0,
+			"missing":          1.0,
+			"coverage_percent": 50.0,
+		},
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	got := make([]map[string]interface{}, 0, len(lines))
+	for _, line := range lines {
+		var v map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &v); err != nil {
+			t.Fatalf("invalid JSON line %s: %v", line, err)
+		}
+
+		got = append(got, v)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JSONLinesWriter wrote\n%s\nwant\n%v", buf.String(), want)
+	}
+}
+
+func TestJSONLinesWriterCoverageMode(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewJSONLinesWriter(&buf, RenderOptions{Mode: ModeCoverage}, nil, false)
+	writer.Started(&Report{ProjectDir: "app"})
+	writer.Found(StringResource{Name: "title", MissingLocales: []string{"de"}})
+	if err := writer.Finish(&Report{}); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 1 || !strings.Contains(buf.String(), `"record":"metadata"`) {
+		t.Errorf("JSONLinesWriter wrote\n%s\nwant only the metadata line", buf.String())
+	}
+}
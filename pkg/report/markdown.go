@@ -0,0 +1,356 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+)
+
+// markdownTemplate is the template for rendering reports as Markdown.
+var markdownTemplate = template.Must(template.New("markdown").Parse(`# {{ .title }}
+{{ if .since_ref }}
+_Only the strings added or changed since ` + "`{{ .since_ref }}`" + ` are included._
+{{ end }}
+{{- if .missing_on }}
+{{ if eq .length 0 -}}
+No missing {{- if eq .outdated_on true }} or outdated {{- end }} translations found.
+{{ else -}}
+{{ .table }}
+{{- end }}
+{{- end }}
+{{- if .coverage_on }}
+## Coverage
+
+Overall coverage is **{{ printf "%.2f" .coverage.Percent }}%** with {{ .coverage.Translated }} translations
+present and {{ .coverage.Missing }} missing.
+{{- if .locale_filters }} Only the locales matching {{ range $i, $filter := .locale_filters }}{{ if $i }}, {{ end }}` + "`{{ $filter }}`" + `{{ end }} are included.
+{{- end }}
+{{ if gt (len .projects) 0 }}
+{{ .projects_table }}
+{{- end }}
+{{- if gt (len .coverage.Locales) 0 }}
+{{ .coverage_table }}
+{{- end }}
+{{- end }}
+{{- if and .missing_on .placeholders_on }}
+## Placeholder Mismatches
+
+{{ if eq .placeholders_length 0 -}}
+No placeholder mismatches found.
+{{ else -}}
+{{ .placeholders_table }}
+{{- end }}
+{{- end }}
+{{- if and .missing_on .identical_on }}
+## Suspicious Translations
+
+{{ if eq .identical_length 0 -}}
+No translations identical to the default strings found.
+{{ else -}}
+The following translations are identical to their default strings.
+
+{{ .identical_table }}
+{{- end }}
+{{- end }}
+{{- if and .missing_on .stale_on }}
+## Stale Translations
+
+{{ if eq .stale_length 0 -}}
+No stale translations found.
+{{ else -}}
+{{ .stale_table }}
+{{- end }}
+{{- end }}
+{{- if .ignored_on }}
+## Ignored
+
+{{ if and (eq (len .ignored.Paths) 0) (eq (len .ignored.Strings) 0) -}}
+No paths or strings were ignored.
+{{ else -}}
+{{ if gt (len .ignored.Paths) 0 -}}
+**Paths:** {{ range $i, $path := .ignored.Paths }}{{ if $i }}, {{ end }}` + "`{{ $path }}`" + `{{ end }}
+{{ if gt (len .ignored.Strings) 0 }}
+{{ end }}
+{{- end -}}
+{{ if gt (len .ignored.Strings) 0 -}}
+**Strings:** {{ range $i, $name := .ignored.Strings }}{{ if $i }}, {{ end }}` + "`{{ $name }}`" + `{{ end }}
+{{ end -}}
+{{ end }}
+{{- end }}
+_Generated using [Android Translations][1] GitHub action._
+
+[1]: https://github.com/ashutoshgngwr/android-translations
+`))
+
+// RenderMarkdown renders the given report as Markdown content.
+func RenderMarkdown(report *Report, opts RenderOptions) (string, error) {
+	length := 0
+	for _, str := range report.Strings {
+		if !str.IsStale() && str.hasTranslationIssues() {
+			length++
+		}
+	}
+
+	staleLocales := report.staleLocales()
+	var content bytes.Buffer
+	err := markdownTemplate.Execute(&content, map[string]interface{}{
+		"title":        opts.Title,
+		"since_ref":    report.Options.SinceRef,
+		"missing_on":   opts.Mode.includesMissing(),
+		"length":       length,
+		"outdated_on":  report.Options.OutdatedLocales,
+		"table":        renderGroupedMarkdownTables(report, opts),
+		"stale_on":     report.Options.CheckStale,
+		"stale_length": len(staleLocales),
+		"stale_table":  renderStaleMarkdownTable(staleLocales),
+
+		"placeholders_on":     report.Options.CheckPlaceholders,
+		"placeholders_length": report.PlaceholderMismatchCount(),
+		"placeholders_table":  renderPlaceholdersMarkdownTable(report),
+
+		"identical_on":     report.Options.CheckIdentical,
+		"identical_length": report.IdenticalCount(),
+		"identical_table":  renderIdenticalMarkdownTable(report),
+
+		"coverage_on":    opts.Mode.includesCoverage(),
+		"coverage":       report.Coverage,
+		"coverage_table": renderCoverageMarkdownTable(opts.coverage(report), opts.LocaleNames),
+		"projects":       report.Projects,
+		"projects_table": renderProjectsMarkdownTable(report.Projects),
+		"locale_filters": report.LocaleFilters,
+
+		"ignored_on": report.Options.ListIgnored,
+		"ignored":    report.Ignored,
+	})
+
+	if err != nil {
+		return "", errors.Wrap(err, "unable to render data as markdown")
+	}
+
+	return content.String(), nil
+}
+
+// renderGroupedMarkdownTables groups the string resources in the report by the
+// attribute selected in 'opts' and pretty prints each group as a Markdown table under
+// its own heading. If 'opts.GroupBy' is GroupByNone, it renders a single table without
+// any heading.
+func renderGroupedMarkdownTables(report *Report, opts RenderOptions) string {
+	if opts.GroupBy == GroupByNone {
+		return renderMarkdownTable(report, report.Strings, opts)
+	}
+
+	groups := make(map[string][]StringResource)
+	for _, item := range report.Strings {
+		if !item.IsStale() && item.hasTranslationIssues() {
+			key := opts.GroupBy.key(item)
+			groups[key] = append(groups[key], item)
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	sections := make([]string, 0, len(keys))
+	for _, key := range keys {
+		sections = append(sections, fmt.Sprintf("### `%s`\n\n%s", key, renderMarkdownTable(report, groups[key], opts)))
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+// renderMarkdownTable pretty prints the given string resources as Markdown table to be
+// used with Markdown format. The names of the string resources link to their
+// declarations relative to 'opts.SourceBaseURL'. The projects of the string resources
+// in merged reports are included unless they are grouped by their projects.
+func renderMarkdownTable(report *Report, items []StringResource, opts RenderOptions) string {
+	var tableContent bytes.Buffer
+	table := tablewriter.NewWriter(&tableContent)
+	table.SetBorders(tablewriter.Border{Left: true, Right: true})
+	table.SetCenterSeparator("|")
+
+	showProject := len(report.Projects) > 0 && opts.GroupBy != GroupByProject
+	header := []string{"#", "Name", "Type", "Default Value", "Missing Locales", "Incomplete Locales"}
+	if showProject {
+		header = append([]string{"#", "Project"}, header[1:]...)
+	}
+
+	if report.Options.OutdatedLocales {
+		header = append(header, "Potentially Outdated Locales")
+	}
+
+	table.SetHeader(header)
+	i := 0
+	for _, item := range items {
+		if item.IsStale() || !item.hasTranslationIssues() {
+			continue
+		}
+
+		name := fmt.Sprintf("`%s`", item.Name)
+		if link := sourceLink(opts.SourceBaseURL, item); link != "" {
+			name = fmt.Sprintf("[%s](%s)", name, link)
+		}
+
+		i++
+		row := []string{
+			fmt.Sprintf("%d", i),
+			name,
+			item.Type,
+			item.Value,
+			item.MissingLocalesString(),
+			item.IncompleteLocalesString(),
+		}
+
+		if showProject {
+			row = append([]string{row[0], item.Project}, row[1:]...)
+		}
+
+		if report.Options.OutdatedLocales {
+			row = append(row, item.OutdatedLocalesString())
+		}
+
+		table.Append(row)
+	}
+
+	table.Render()
+	return tableContent.String()
+}
+
+// renderStaleMarkdownTable pretty prints the names of stale string resources grouped
+// by their locales as Markdown table to be used with Markdown format.
+func renderStaleMarkdownTable(staleLocales []staleLocale) string {
+	var tableContent bytes.Buffer
+	table := tablewriter.NewWriter(&tableContent)
+	table.SetBorders(tablewriter.Border{Left: true, Right: true})
+	table.SetCenterSeparator("|")
+	table.SetAutoWrapText(false)
+	table.SetHeader([]string{"#", "Locale", "Stale Strings"})
+	for i, stale := range staleLocales {
+		names := make([]string, 0, len(stale.Names))
+		for _, name := range stale.Names {
+			names = append(names, fmt.Sprintf("`%s`", name))
+		}
+
+		table.Append([]string{fmt.Sprintf("%d", 1+i), stale.Locale, strings.Join(names, ", ")})
+	}
+
+	table.Render()
+	return tableContent.String()
+}
+
+// renderIdenticalMarkdownTable pretty prints the string resources with translations
+// identical to their default strings as Markdown table to be used with Markdown format.
+func renderIdenticalMarkdownTable(report *Report) string {
+	var tableContent bytes.Buffer
+	table := tablewriter.NewWriter(&tableContent)
+	table.SetBorders(tablewriter.Border{Left: true, Right: true})
+	table.SetCenterSeparator("|")
+	table.SetAutoWrapText(false)
+	table.SetHeader([]string{"#", "Name", "Default Value", "Identical Locales"})
+	i := 0
+	for _, item := range report.Strings {
+		if len(item.IdenticalLocales) == 0 {
+			continue
+		}
+
+		i++
+		table.Append([]string{fmt.Sprintf("%d", i), fmt.Sprintf("`%s`", item.Name), item.Value, strings.Join(item.IdenticalLocales, ", ")})
+	}
+
+	table.Render()
+	return tableContent.String()
+}
+
+// renderPlaceholdersMarkdownTable pretty prints the placeholder mismatches of the
+// string resources in the report as Markdown table to be used with Markdown format.
+func renderPlaceholdersMarkdownTable(report *Report) string {
+	var tableContent bytes.Buffer
+	table := tablewriter.NewWriter(&tableContent)
+	table.SetBorders(tablewriter.Border{Left: true, Right: true})
+	table.SetCenterSeparator("|")
+	table.SetAutoWrapText(false)
+	table.SetHeader([]string{"#", "Name", "Locale", "Mismatches"})
+	i := 0
+	for _, item := range report.Strings {
+		locales := make([]string, 0, len(item.PlaceholderMismatches))
+		for locale := range item.PlaceholderMismatches {
+			locales = append(locales, locale)
+		}
+
+		sort.Strings(locales)
+		for _, locale := range locales {
+			i++
+			mismatches := make([]string, 0, len(item.PlaceholderMismatches[locale]))
+			for _, mismatch := range item.PlaceholderMismatches[locale] {
+				mismatches = append(mismatches, fmt.Sprintf("`%s`", mismatch))
+			}
+
+			table.Append([]string{fmt.Sprintf("%d", i), fmt.Sprintf("`%s`", item.Name), locale, strings.Join(mismatches, ", ")})
+		}
+	}
+
+	table.Render()
+	return tableContent.String()
+}
+
+// renderProjectsMarkdownTable pretty prints the coverage of each project of a merged
+// report as Markdown table to be used with Markdown format.
+func renderProjectsMarkdownTable(projects []ProjectCoverage) string {
+	var tableContent bytes.Buffer
+	table := tablewriter.NewWriter(&tableContent)
+	table.SetBorders(tablewriter.Border{Left: true, Right: true})
+	table.SetCenterSeparator("|")
+	table.SetAutoWrapText(false)
+	table.SetHeader([]string{"#", "Project", "Translated", "Missing", "Coverage"})
+	for i, project := range projects {
+		table.Append([]string{
+			fmt.Sprintf("%d", 1+i),
+			project.Project,
+			fmt.Sprintf("%d", project.Coverage.Translated),
+			fmt.Sprintf("%d", project.Coverage.Missing),
+			fmt.Sprintf("%.2f%%", project.Coverage.Percent),
+		})
+	}
+
+	table.Render()
+	return tableContent.String()
+}
+
+// renderCoverageMarkdownTable pretty prints the coverage of each locale as Markdown
+// table to be used with Markdown format. If 'names' is true, the names of the locales
+// are included.
+func renderCoverageMarkdownTable(coverage Coverage, names bool) string {
+	var tableContent bytes.Buffer
+	table := tablewriter.NewWriter(&tableContent)
+	table.SetBorders(tablewriter.Border{Left: true, Right: true})
+	table.SetCenterSeparator("|")
+	table.SetAutoWrapText(false)
+	header := []string{"#", "Locale", "Translated", "Missing", "Coverage"}
+	if names {
+		header = []string{"#", "Locale", "Name", "Translated", "Missing", "Coverage"}
+	}
+
+	table.SetHeader(header)
+	for i, localeCoverage := range coverage.Locales {
+		row := []string{fmt.Sprintf("%d", 1+i), localeCoverage.Locale}
+		if names {
+			row = append(row, localeCoverage.Name)
+		}
+
+		table.Append(append(row,
+			fmt.Sprintf("%d", localeCoverage.Translated),
+			fmt.Sprintf("%d", localeCoverage.Missing),
+			fmt.Sprintf("%.2f%%", localeCoverage.Percent),
+		))
+	}
+
+	table.Render()
+	return tableContent.String()
+}
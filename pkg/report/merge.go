@@ -0,0 +1,148 @@
+package report
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// ProjectCoverage declares the translation coverage of a single project in a merged
+// report.
+type ProjectCoverage struct {
+	Project  string   `json:"project"`
+	Coverage Coverage `json:"coverage"`
+}
+
+// MergeReports merges the reports of several projects into a single report. 'projects'
+// contains the names of the projects, e.g. their directories relative to the current
+// working directory, in the same order as 'reports'. Each string resource is labelled
+// with the name of its project, and its file and resource directory are prefixed with
+// it. The coverage of the merged report is the sum of the coverage of the projects.
+// The options, the default language and the generation time of the first report are
+// used.
+func MergeReports(projects []string, reports []*Report) *Report {
+	merged := &Report{
+		Strings:      []StringResource{},
+		ConfigErrors: []string{},
+		Warnings:     []string{},
+		Ignored:      IgnoredItems{Paths: []string{}, Strings: []string{}},
+		Projects:     make([]ProjectCoverage, 0, len(projects)),
+	}
+
+	if len(reports) > 0 {
+		merged.Options = reports[0].Options
+		merged.DefaultLanguage = reports[0].DefaultLanguage
+		merged.GeneratedAt = reports[0].GeneratedAt
+	}
+
+	ignoredStrings := make(map[string]bool)
+	localeFilters := make(map[string]bool)
+	locales := make(map[string]bool)
+	for i, report := range reports {
+		project := projectName(projects[i])
+		for _, str := range report.Strings {
+			merged.Strings = append(merged.Strings, withProject(project, str))
+		}
+
+		for _, locale := range report.Locales {
+			locales[locale] = true
+		}
+
+		for _, configError := range report.ConfigErrors {
+			merged.ConfigErrors = append(merged.ConfigErrors, project+": "+configError)
+		}
+
+		for _, warning := range report.Warnings {
+			merged.Warnings = append(merged.Warnings, project+": "+warning)
+		}
+
+		for _, ignoredPath := range report.Ignored.Paths {
+			merged.Ignored.Paths = append(merged.Ignored.Paths, prefixPath(project, ignoredPath))
+		}
+
+		for _, name := range report.Ignored.Strings {
+			ignoredStrings[name] = true
+		}
+
+		for _, filter := range report.LocaleFilters {
+			localeFilters[filter] = true
+		}
+
+		merged.ValuesFiles = append(merged.ValuesFiles, report.ValuesFiles...)
+		merged.Projects = append(merged.Projects, ProjectCoverage{Project: project, Coverage: report.Coverage})
+	}
+
+	merged.Coverage = mergeCoverage(merged.Projects)
+	merged.Ignored.Strings = sortedKeys(ignoredStrings)
+	merged.LocaleFilters = sortedKeys(localeFilters)
+	merged.Locales = sortedKeys(locales)
+	sort.Stable(stringResources(merged.Strings))
+	return merged
+}
+
+// projectName returns the name of the project in the given directory used in merged
+// reports.
+func projectName(projectDir string) string {
+	return filepath.ToSlash(filepath.Clean(projectDir))
+}
+
+// withProject labels the given string resource with the name of its project and
+// prefixes its file and resource directory with it.
+func withProject(project string, str StringResource) StringResource {
+	str.Project = project
+	str.File = prefixPath(project, str.File)
+	str.ResourceDir = prefixPath(project, str.ResourceDir)
+	return str
+}
+
+// mergeCoverage sums the coverage of the given projects.
+func mergeCoverage(projects []ProjectCoverage) Coverage {
+	locales := make(map[string]bool)
+	translated := make(map[string]int)
+	missing := make(map[string]int)
+	for _, project := range projects {
+		for _, localeCoverage := range project.Coverage.Locales {
+			locales[localeCoverage.Locale] = true
+			translated[localeCoverage.Locale] += localeCoverage.Translated
+			missing[localeCoverage.Locale] += localeCoverage.Missing
+		}
+	}
+
+	coverage := Coverage{Locales: make([]LocaleCoverage, 0, len(translated))}
+	for _, locale := range sortedKeys(locales) {
+		localeCoverage := LocaleCoverage{
+			Locale:     locale,
+			Translated: translated[locale],
+			Missing:    missing[locale],
+			Percent:    percent(translated[locale], translated[locale]+missing[locale]),
+		}
+
+		coverage.Translated += localeCoverage.Translated
+		coverage.Missing += localeCoverage.Missing
+		coverage.Locales = append(coverage.Locales, localeCoverage)
+	}
+
+	coverage.Percent = percent(coverage.Translated, coverage.Translated+coverage.Missing)
+	return coverage
+}
+
+// prefixPath joins the given project name and the path relative to the project. It
+// returns the path as is if it is empty.
+func prefixPath(project, relPath string) string {
+	if relPath == "" {
+		return relPath
+	}
+
+	return path.Join(project, relPath)
+}
+
+// sortedKeys returns the keys of the given set in sorted order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
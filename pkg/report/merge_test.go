@@ -0,0 +1,102 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeReports(t *testing.T) {
+	reports := []*Report{
+		{
+			DefaultLanguage: "en",
+			Locales:         []string{"de", "fr"},
+			Strings: []StringResource{
+				{Name: "title", File: "res/values/strings.xml", ResourceDir: "res", MissingLocales: []string{"fr"}},
+			},
+			ConfigErrors:  []string{"locale \"fr\" is not declared"},
+			Warnings:      []string{},
+			Ignored:       IgnoredItems{Paths: []string{"build"}, Strings: []string{"app_name"}},
+			LocaleFilters: []string{"de"},
+			Coverage: Coverage{Locales: []LocaleCoverage{
+				{Locale: "de", Translated: 1},
+				{Locale: "fr", Missing: 1},
+			}},
+		},
+		{
+			DefaultLanguage: "en",
+			Locales:         []string{"de"},
+			Strings: []StringResource{
+				{Name: "body", File: "res/values/strings.xml", ResourceDir: "res", MissingLocales: []string{"de"}},
+			},
+			ConfigErrors: []string{},
+			Warnings:     []string{"values-b+xx is never served"},
+			Ignored:      IgnoredItems{Paths: []string{}, Strings: []string{"app_name", "version"}},
+			Coverage: Coverage{Locales: []LocaleCoverage{
+				{Locale: "de", Translated: 1, Missing: 1},
+			}},
+		},
+	}
+
+	merged := MergeReports([]string{"apps/one", "apps/two/"}, reports)
+	if merged.DefaultLanguage != "en" {
+		t.Errorf("DefaultLanguage = %q, want %q", merged.DefaultLanguage, "en")
+	}
+
+	wantStrings := []StringResource{
+		{
+			Project:        "apps/two",
+			Name:           "body",
+			File:           "apps/two/res/values/strings.xml",
+			ResourceDir:    "apps/two/res",
+			MissingLocales: []string{"de"},
+		},
+		{
+			Project:        "apps/one",
+			Name:           "title",
+			File:           "apps/one/res/values/strings.xml",
+			ResourceDir:    "apps/one/res",
+			MissingLocales: []string{"fr"},
+		},
+	}
+
+	if !reflect.DeepEqual(merged.Strings, wantStrings) {
+		t.Errorf("Strings = %+v, want %+v", merged.Strings, wantStrings)
+	}
+
+	checks := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{name: "Locales", got: merged.Locales, want: []string{"de", "fr"}},
+		{name: "ConfigErrors", got: merged.ConfigErrors, want: []string{"apps/one: locale \"fr\" is not declared"}},
+		{name: "Warnings", got: merged.Warnings, want: []string{"apps/two: values-b+xx is never served"}},
+		{name: "Ignored.Paths", got: merged.Ignored.Paths, want: []string{"apps/one/build"}},
+		{name: "Ignored.Strings", got: merged.Ignored.Strings, want: []string{"app_name", "version"}},
+		{name: "LocaleFilters", got: merged.LocaleFilters, want: []string{"de"}},
+	}
+
+	for _, check := range checks {
+		if !reflect.DeepEqual(check.got, check.want) {
+			t.Errorf("%s = %q, want %q", check.name, check.got, check.want)
+		}
+	}
+
+	wantCoverage := Coverage{
+		Translated: 2,
+		Missing:    2,
+		Percent:    50,
+		Locales: []LocaleCoverage{
+			{Locale: "de", Translated: 2, Missing: 1, Percent: percent(2, 3)},
+			{Locale: "fr", Translated: 0, Missing: 1, Percent: 0},
+		},
+	}
+
+	if !reflect.DeepEqual(merged.Coverage, wantCoverage) {
+		t.Errorf("Coverage = %+v, want %+v", merged.Coverage, wantCoverage)
+	}
+
+	if len(merged.Projects) != 2 || merged.Projects[1].Project != "apps/two" {
+		t.Errorf("Projects = %+v, want the coverage of apps/one and apps/two", merged.Projects)
+	}
+}
@@ -0,0 +1,131 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mode declares the sections to include in the rendered reports.
+type Mode string
+
+// modes of the rendered reports.
+const (
+	ModeMissing  Mode = "missing"  // only the translation issues
+	ModeCoverage Mode = "coverage" // only the translation coverage
+	ModeAll      Mode = "all"      // both the translation issues and coverage
+)
+
+// ParseMode returns the Mode with the given name or an error if no such mode exists.
+func ParseMode(name string) (Mode, error) {
+	switch mode := Mode(name); mode {
+	case ModeMissing, ModeCoverage, ModeAll:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown report mode %s", name)
+	}
+}
+
+// includesMissing returns true if the mode includes the translation issues.
+func (mode Mode) includesMissing() bool {
+	return mode != ModeCoverage
+}
+
+// includesCoverage returns true if the mode includes the translation coverage.
+func (mode Mode) includesCoverage() bool {
+	return mode == ModeCoverage || mode == ModeAll
+}
+
+// GroupBy declares the attribute to group the string resources by in the rendered
+// reports.
+type GroupBy string
+
+// attributes to group the string resources by.
+const (
+	GroupByNone      GroupBy = ""          // no grouping
+	GroupByModule    GroupBy = "module"    // the Gradle module of the default resource
+	GroupByDirectory GroupBy = "directory" // the resource directory of the default resource
+	GroupByProject   GroupBy = "project"   // the project of a merged report
+)
+
+// ParseGroupBy returns the GroupBy with the given name or an error if no such attribute
+// exists. An empty name disables grouping.
+func ParseGroupBy(name string) (GroupBy, error) {
+	switch groupBy := GroupBy(name); groupBy {
+	case GroupByNone, GroupByModule, GroupByDirectory, GroupByProject:
+		return groupBy, nil
+	default:
+		return "", fmt.Errorf("unknown group by attribute %s", name)
+	}
+}
+
+// key returns the value of the attribute of the given string resource. The modules
+// of the string resources in merged reports are prefixed with their projects.
+func (groupBy GroupBy) key(str StringResource) string {
+	switch groupBy {
+	case GroupByModule:
+		if str.Project != "" {
+			return str.Project + " " + str.Module
+		}
+
+		return str.Module
+	case GroupByProject:
+		return str.Project
+	case GroupByDirectory:
+		return str.ResourceDir
+	default:
+		return ""
+	}
+}
+
+// RenderOptions declares the options for rendering reports.
+type RenderOptions struct {
+	// Title is the heading of the Markdown content.
+	Title string
+
+	// Mode selects the sections to include in the reports. If empty, ModeMissing
+	// is used.
+	Mode Mode
+
+	// GroupBy selects the attribute to group the string resources by in the
+	// Markdown content.
+	GroupBy GroupBy
+
+	// SourceBaseURL is the URL of the project directory in a source browser, e.g.
+	// 'https://github.com/owner/repo/blob/main', to link the strings to their
+	// declarations in the HTML and Markdown content.
+	SourceBaseURL string
+
+	// LocaleNames, if true, includes the English names of the locales, e.g. 'Chinese
+	// (China)' for 'zh-CN', with their coverage.
+	LocaleNames bool
+}
+
+// coverage returns the coverage of the given report with the names of the locales if
+// 'opts.LocaleNames' is true.
+func (opts RenderOptions) coverage(report *Report) Coverage {
+	if opts.LocaleNames {
+		return report.Coverage.withLocaleNames()
+	}
+
+	return report.Coverage
+}
+
+// sourceLink returns the URL of the declaration of the given string resource relative
+// to 'baseURL', e.g. 'https://github.com/owner/repo/blob/main'. If 'baseURL' is empty,
+// the path relative to the project directory is used as the URL.
+func sourceLink(baseURL string, str StringResource) string {
+	link := str.File
+	if link == "" {
+		return ""
+	}
+
+	if baseURL != "" {
+		link = strings.TrimSuffix(baseURL, "/") + "/" + link
+	}
+
+	if str.Line > 0 {
+		link = fmt.Sprintf("%s#L%d", link, str.Line)
+	}
+
+	return link
+}
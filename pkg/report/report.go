@@ -0,0 +1,297 @@
+// Package report scans Android projects for missing and potentially outdated
+// translations and renders the findings in various formats.
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ashutoshgngwr/android-translations/pkg/resources"
+)
+
+// Options declares the options for scanning an Android project.
+type Options struct {
+	// OutdatedLocales, if true, also finds potentially outdated translations.
+	OutdatedLocales bool
+
+	// LocaleAliases maps 'values-' suffixes to their canonical locales. If nil,
+	// resources.DefaultLocaleAliases is used.
+	LocaleAliases map[string]string
+
+	// CheckLocaleConfig, if true, validates the locales declared in the app's
+	// localeConfig against the translations.
+	CheckLocaleConfig bool
+
+	// CheckLocaleSupport, if true, warns about locales that are never served to
+	// the users.
+	CheckLocaleSupport bool
+
+	// CheckStale, if true, also finds stale translations, i.e. the translations
+	// whose resources no longer exist in the default locale.
+	CheckStale bool
+
+	// CheckPlaceholders, if true, compares the format specifiers, '\n' escapes and
+	// '{placeholder}' tokens of the default strings with their translations.
+	CheckPlaceholders bool
+
+	// PathFilter filters the paths, relative to the project directory, to scan for
+	// values files.
+	PathFilter resources.PathFilter
+
+	// IgnoreStrings lists the regular expressions for the names of the string
+	// resources to ignore. The expressions must match the complete name, so an
+	// exact name matches only itself.
+	IgnoreStrings []string
+
+	// ListIgnored, if true, lists the paths skipped by the PathFilter and the names
+	// of the string resources matched by IgnoreStrings in the report.
+	ListIgnored bool
+
+	// SinceRef, if not empty, restricts the report to the default string resources
+	// added or changed since the merge base of the given git ref and 'HEAD', e.g.
+	// 'origin/main'. Stale translations are restricted to the resources removed
+	// since the merge base.
+	SinceRef string
+
+	// CheckIdentical, if true, also finds the translations that are identical to
+	// their default strings. See resources.IsIdenticalTranslation.
+	CheckIdentical bool
+
+	// IgnoreIdentical lists the regular expressions for the names or the default
+	// values of the string resources whose translations may be identical to their
+	// default strings, e.g. brand names. The expressions must match the complete name
+	// or value.
+	IgnoreIdentical []string
+
+	// RespectToolsIgnore, if true, honors the Android Lint issue ids in the
+	// 'tools:ignore' attributes of the resources. 'MissingTranslation' on a default
+	// resource excludes it from the missing translations and the coverage,
+	// 'ExtraTranslation' on a translation excludes it from the stale translations,
+	// and 'all' on a default resource or a translation excludes it from all the
+	// checks. See resources.Resource.IsToolsIgnored.
+	RespectToolsIgnore bool
+
+	// Cache, if not nil, caches the parsed values files to only parse the files
+	// that changed since the last scan, e.g. while watching a project for changes.
+	Cache *resources.ParseCache
+
+	// DefaultLocale, if not empty, selects the locale whose string resources are
+	// used as the default string resources instead of the resources in 'values'
+	// directories, e.g. 'en' for projects keeping the source language in
+	// 'values-en'. The resources in 'values' directories are ignored in that case.
+	DefaultLocale string
+
+	// Locales, if not empty, restricts the report to the translations of the given
+	// locales. See resources.IsLocaleIncluded.
+	Locales []string
+
+	// GradleLocaleFilters, if true and Locales is empty, restricts the report to the
+	// locales declared using 'resConfigs' or 'localeFilters' in the Gradle build
+	// scripts. See resources.FindGradleLocaleFilters.
+	GradleLocaleFilters bool
+
+	// Jobs is the number of values files to parse concurrently. If it is less than 1,
+	// the number of CPUs is used.
+	Jobs int
+
+	// Started, if not nil, is called once the metadata of the report, i.e. its project
+	// directory, default language and locales, is known and before any string resource
+	// is found, e.g. to stream the findings along with their metadata.
+	Started func(report *Report)
+
+	// Found, if not nil, is called with each string resource with any issues as soon
+	// as it is found. The resources are found in no particular order.
+	Found func(str StringResource)
+}
+
+// Report declares the findings of scanning an Android project.
+type Report struct {
+	// Options used for scanning the project.
+	Options Options
+
+	// Strings contains the resources with missing, incomplete, potentially
+	// outdated or stale translations sorted by their names.
+	Strings []StringResource
+
+	// DefaultLanguage is the language of the default string resources.
+	DefaultLanguage string
+
+	// Coverage contains the translation coverage of the project and its locales.
+	Coverage Coverage
+
+	// ConfigErrors contains the problems in the project's configuration that
+	// should fail the build.
+	ConfigErrors []string
+
+	// Warnings contains the problems that didn't prevent scanning the project.
+	Warnings []string
+
+	// Ignored contains the paths and string resources ignored while scanning the
+	// project. It is only populated if Options.ListIgnored is true.
+	Ignored IgnoredItems
+
+	// ValuesFiles contains the paths of the scanned values files.
+	ValuesFiles []string
+
+	// LocaleFilters contains the filters that the translated locales were restricted
+	// to. It is empty if all locales were included.
+	LocaleFilters []string
+
+	// Projects contains the coverage of each project if the report was merged from
+	// the reports of several projects. See MergeReports.
+	Projects []ProjectCoverage
+
+	// ProjectDir is the directory of the scanned project. It is empty if the report
+	// was merged from the reports of several projects.
+	ProjectDir string
+
+	// GeneratedAt is the time when the project was scanned.
+	GeneratedAt time.Time
+
+	// Locales contains the translated locales of the project sorted by their names.
+	Locales []string
+}
+
+// IgnoredItems declares the paths and the names of the string resources that were
+// ignored while scanning a project.
+type IgnoredItems struct {
+	Paths   []string `json:"paths"`
+	Strings []string `json:"strings"`
+}
+
+// StringResource declares the output structure for a single string resource.
+type StringResource struct {
+	Project           string              `json:"project,omitempty"`
+	Name              string              `json:"name"`
+	Type              string              `json:"type"`
+	Value             string              `json:"value"`
+	Module            string              `json:"module"`
+	ResourceDir       string              `json:"resource_dir"`
+	File              string              `json:"file"`
+	Line              int                 `json:"line"`
+	MissingLocales    []string            `json:"missing_locales"`
+	EmptyLocales      []string            `json:"empty_locales,omitempty"`
+	OutdatedLocales   []string            `json:"outdated_locales"`
+	MissingQuantities map[string][]string `json:"missing_quantities,omitempty"`
+	ItemCountMismatch map[string]int      `json:"item_count_mismatch,omitempty"`
+	StaleLocales      []string            `json:"stale_locales,omitempty"`
+	IdenticalLocales  []string            `json:"identical_locales,omitempty"`
+
+	PlaceholderMismatches map[string][]string `json:"placeholder_mismatches,omitempty"`
+
+	// Default is the parsed default resource. It is empty for stale resources.
+	Default resources.Resource `json:"-"`
+
+	// Stale contains the parsed stale translations keyed by their locales.
+	Stale map[string]resources.Resource `json:"-"`
+}
+
+// hasTranslationIssues returns true if the resource has missing, incomplete or
+// potentially outdated translations.
+func (res StringResource) hasTranslationIssues() bool {
+	issueCount := len(res.MissingLocales) + len(res.OutdatedLocales)
+	issueCount += len(res.MissingQuantities) + len(res.ItemCountMismatch)
+	return issueCount > 0
+}
+
+// IsStale returns true if the resource no longer exists in the default locale but
+// its translations do.
+func (res StringResource) IsStale() bool {
+	return len(res.StaleLocales) > 0
+}
+
+// MissingLocalesString joins the MissingLocales slice using ", " separator
+func (res StringResource) MissingLocalesString() string {
+	if len(res.MissingLocales) == 0 {
+		return "-"
+	}
+
+	return strings.Join(res.MissingLocales, ", ")
+}
+
+// OutdatesLocalesString joins the OutdatesLocales slice using ", " separator
+func (res StringResource) OutdatedLocalesString() string {
+	if len(res.OutdatedLocales) == 0 {
+		return "-"
+	}
+
+	return strings.Join(res.OutdatedLocales, ", ")
+}
+
+// IncompleteLocalesString describes the locales with missing 'plurals' quantities or
+// mismatching 'string-array' item counts, e.g. 'ru (few, many)' or 'de (2 items)',
+// using ", " separator.
+func (res StringResource) IncompleteLocalesString() string {
+	incomplete := make([]string, 0)
+	for locale, quantities := range res.MissingQuantities {
+		incomplete = append(incomplete, fmt.Sprintf("%s (%s)", locale, strings.Join(quantities, ", ")))
+	}
+
+	for locale, count := range res.ItemCountMismatch {
+		incomplete = append(incomplete, fmt.Sprintf("%s (%d items)", locale, count))
+	}
+
+	if len(incomplete) == 0 {
+		return "-"
+	}
+
+	sort.Strings(incomplete)
+	return strings.Join(incomplete, ", ")
+}
+
+// IdenticalCount returns the count of the translations that are identical to their
+// default strings.
+func (report *Report) IdenticalCount() int {
+	count := 0
+	for _, str := range report.Strings {
+		count += len(str.IdenticalLocales)
+	}
+
+	return count
+}
+
+// PlaceholderMismatchCount returns the count of the translations whose placeholders
+// don't match their default strings.
+func (report *Report) PlaceholderMismatchCount() int {
+	count := 0
+	for _, str := range report.Strings {
+		count += len(str.PlaceholderMismatches)
+	}
+
+	return count
+}
+
+// staleLocale declares the stale translations of a single locale.
+type staleLocale struct {
+	Locale string
+	Names  []string
+}
+
+// staleLocales groups the names of the stale string resources in the report by their
+// locales. The result is sorted by locales.
+func (report *Report) staleLocales() []staleLocale {
+	names := make(map[string][]string)
+	for _, str := range report.Strings {
+		for _, locale := range str.StaleLocales {
+			names[locale] = append(names[locale], str.Name)
+		}
+	}
+
+	stale := make([]staleLocale, 0, len(names))
+	for locale := range names {
+		stale = append(stale, staleLocale{Locale: locale, Names: names[locale]})
+	}
+
+	sort.Slice(stale, func(i, j int) bool { return stale[i].Locale < stale[j].Locale })
+	return stale
+}
+
+// stringResources is a named type for StringResource slice that implements
+// the sort.Interface for sorting slices.
+type stringResources []StringResource
+
+func (res stringResources) Len() int           { return len(res) }
+func (res stringResources) Swap(i, j int)      { res[i], res[j] = res[j], res[i] }
+func (res stringResources) Less(i, j int) bool { return res[i].Name < res[j].Name }
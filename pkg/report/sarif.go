@@ -0,0 +1,226 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ashutoshgngwr/android-translations/pkg/resources"
+	"github.com/pkg/errors"
+)
+
+// sarifSchema is the URI of the JSON schema of SARIF 2.1.0 documents.
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// SARIF rule ids of the findings.
+const (
+	ruleMissing     = "missing-translation"
+	ruleIncomplete  = "incomplete-translation"
+	ruleOutdated    = "outdated-translation"
+	ruleStale       = "stale-translation"
+	rulePlaceholder = "placeholder-mismatch"
+	ruleIdentical   = "identical-translation"
+)
+
+// sarifRules declares the rules reported in the SARIF documents.
+var sarifRules = []sarifRule{
+	{ID: ruleMissing, ShortDescription: sarifMessage{"Missing translation"}, DefaultConfiguration: sarifConfiguration{"warning"}},
+	{ID: ruleIncomplete, ShortDescription: sarifMessage{"Incomplete plurals or string-array translation"}, DefaultConfiguration: sarifConfiguration{"warning"}},
+	{ID: ruleOutdated, ShortDescription: sarifMessage{"Potentially outdated translation"}, DefaultConfiguration: sarifConfiguration{"note"}},
+	{ID: ruleStale, ShortDescription: sarifMessage{"Stale translation of a removed string"}, DefaultConfiguration: sarifConfiguration{"note"}},
+	{ID: rulePlaceholder, ShortDescription: sarifMessage{"Placeholders of translation don't match the default string"}, DefaultConfiguration: sarifConfiguration{"error"}},
+	{ID: ruleIdentical, ShortDescription: sarifMessage{"Translation is identical to the default string"}, DefaultConfiguration: sarifConfiguration{"note"}},
+}
+
+// sarifLog declares data structure for marshalling SARIF 2.1.0 documents.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+// sarifRun declares data structure for marshalling 'run' objects in SARIF documents.
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name           string      `json:"name"`
+			InformationURI string      `json:"informationUri"`
+			Rules          []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results    []sarifResult      `json:"results"`
+	Properties sarifRunProperties `json:"properties"`
+}
+
+// sarifRunProperties declares data structure for marshalling the property bags of 'run'
+// objects in SARIF documents. They contain the version of the structure of the metadata
+// and the metadata of the run, and the ignored items if they were listed.
+type sarifRunProperties struct {
+	SchemaVersion int           `json:"schema_version"`
+	Metadata      Metadata      `json:"metadata"`
+	Ignored       *IgnoredItems `json:"ignored,omitempty"`
+}
+
+// sarifRule declares data structure for marshalling 'reportingDescriptor' objects in
+// SARIF documents.
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+// sarifConfiguration declares data structure for marshalling 'reportingConfiguration'
+// objects in SARIF documents.
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+// sarifMessage declares data structure for marshalling 'message' objects in SARIF
+// documents.
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+// sarifResult declares data structure for marshalling 'result' objects in SARIF
+// documents.
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+// sarifLocation declares data structure for marshalling 'location' objects in SARIF
+// documents.
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI       string `json:"uri"`
+			URIBaseID string `json:"uriBaseId"`
+		} `json:"artifactLocation"`
+		Region *sarifRegion `json:"region,omitempty"`
+	} `json:"physicalLocation"`
+}
+
+// sarifRegion declares data structure for marshalling 'region' objects in SARIF
+// documents.
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// RenderSARIF renders the findings in the report as a SARIF 2.1.0 document, e.g. for
+// uploading to GitHub code scanning. Each result points at the declaration of the
+// default string, or of the translation for stale translations. The paths are relative
+// to the current working directory, which should be the root of the repository.
+func RenderSARIF(report *Report) (string, error) {
+	var run sarifRun
+	run.Tool.Driver.Name = "android-translations"
+	run.Tool.Driver.InformationURI = "https://github.com/ashutoshgngwr/android-translations"
+	run.Tool.Driver.Rules = sarifRules
+	run.Results = make([]sarifResult, 0)
+	run.Properties = sarifRunProperties{SchemaVersion: SchemaVersion, Metadata: report.Metadata()}
+	if report.Options.ListIgnored {
+		run.Properties.Ignored = &report.Ignored
+	}
+
+	for _, str := range report.Strings {
+		run.Results = append(run.Results, sarifResults(str)...)
+	}
+
+	content, err := json.MarshalIndent(sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal content as SARIF")
+	}
+
+	return string(content), nil
+}
+
+// sarifResults returns the SARIF results for the findings of the given string resource.
+func sarifResults(str StringResource) []sarifResult {
+	results := make([]sarifResult, 0)
+	if str.IsStale() {
+		for _, locale := range str.StaleLocales {
+			message := fmt.Sprintf("'%s' no longer exists in the default locale but its translation for %s does", str.Name, locale)
+			results = append(results, newSARIFResult(ruleStale, message, str.Stale[locale]))
+		}
+
+		return results
+	}
+
+	if len(str.MissingLocales) > 0 {
+		message := fmt.Sprintf("'%s' is missing translations for %s", str.Name, str.MissingLocalesString())
+		results = append(results, newSARIFResult(ruleMissing, message, str.Default))
+	}
+
+	if len(str.MissingQuantities) > 0 || len(str.ItemCountMismatch) > 0 {
+		message := fmt.Sprintf("'%s' has incomplete translations for %s", str.Name, str.IncompleteLocalesString())
+		results = append(results, newSARIFResult(ruleIncomplete, message, str.Default))
+	}
+
+	if len(str.OutdatedLocales) > 0 {
+		message := fmt.Sprintf("'%s' has potentially outdated translations for %s", str.Name, str.OutdatedLocalesString())
+		results = append(results, newSARIFResult(ruleOutdated, message, str.Default))
+	}
+
+	if len(str.IdenticalLocales) > 0 {
+		message := fmt.Sprintf("translations of '%s' for %s are identical to the default string", str.Name, strings.Join(str.IdenticalLocales, ", "))
+		results = append(results, newSARIFResult(ruleIdentical, message, str.Default))
+	}
+
+	locales := make([]string, 0, len(str.PlaceholderMismatches))
+	for locale := range str.PlaceholderMismatches {
+		locales = append(locales, locale)
+	}
+
+	sort.Strings(locales)
+	for _, locale := range locales {
+		message := fmt.Sprintf("placeholders of '%s' translation for %s don't match the default string: %s",
+			str.Name, locale, strings.Join(str.PlaceholderMismatches[locale], ", "))
+		results = append(results, newSARIFResult(rulePlaceholder, message, str.Default))
+	}
+
+	return results
+}
+
+// newSARIFResult creates a SARIF result for the given rule located at the declaration of
+// the given resource.
+func newSARIFResult(ruleID, message string, res resources.Resource) sarifResult {
+	result := sarifResult{RuleID: ruleID, Message: sarifMessage{message}}
+	for _, rule := range sarifRules {
+		if rule.ID == ruleID {
+			result.Level = rule.DefaultConfiguration.Level
+		}
+	}
+
+	location := sarifLocation{}
+	location.PhysicalLocation.ArtifactLocation.URI = sarifURI(res.File)
+	location.PhysicalLocation.ArtifactLocation.URIBaseID = "%SRCROOT%"
+	if res.Line > 0 {
+		location.PhysicalLocation.Region = &sarifRegion{StartLine: res.Line}
+	}
+
+	result.Locations = []sarifLocation{location}
+	return result
+}
+
+// sarifURI returns the path of the given file relative to the current working directory
+// using '/' as separator.
+func sarifURI(file string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if abs, err := filepath.Abs(file); err == nil {
+			if rel, err := filepath.Rel(wd, abs); err == nil {
+				file = rel
+			}
+		}
+	}
+
+	return filepath.ToSlash(file)
+}
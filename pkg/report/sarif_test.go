@@ -0,0 +1,85 @@
+package report
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/ashutoshgngwr/android-translations/pkg/resources"
+)
+
+func TestRenderSARIF(t *testing.T) {
+	title := resources.Resource{Type: resources.TypeString, Name: "title", File: "app/res/values/strings.xml", Line: 3}
+	removed := resources.Resource{Type: resources.TypeString, Name: "removed", File: "app/res/values-de/strings.xml"}
+	report := &Report{
+		DefaultLanguage: "en",
+		Locales:         []string{"de", "fr"},
+		ProjectDir:      "app",
+		Options:         Options{ListIgnored: true},
+		Ignored:         IgnoredItems{Paths: []string{"build"}, Strings: []string{}},
+		Strings: []StringResource{
+			{
+				Name:                  "title",
+				Default:               title,
+				MissingLocales:        []string{"de", "fr"},
+				PlaceholderMismatches: map[string][]string{"de": {"missing %1$s"}},
+			},
+			{
+				Name:         "removed",
+				StaleLocales: []string{"de"},
+				Stale:        map[string]resources.Resource{"de": removed},
+			},
+		},
+	}
+
+	content, err := RenderSARIF(report)
+	if err != nil {
+		t.Fatalf("RenderSARIF() error = %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal([]byte(content), &log); err != nil {
+		t.Fatalf("RenderSARIF() rendered invalid JSON: %v", err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("RenderSARIF() = version %s with %d runs, want version 2.1.0 with 1 run", log.Version, len(log.Runs))
+	}
+
+	run := log.Runs[0]
+	if run.Properties.SchemaVersion != SchemaVersion || !reflect.DeepEqual(run.Properties.Metadata.Locales, report.Locales) {
+		t.Errorf("RenderSARIF() properties = %+v, want the schema version and metadata", run.Properties)
+	}
+
+	if run.Properties.Ignored == nil || !reflect.DeepEqual(run.Properties.Ignored.Paths, []string{"build"}) {
+		t.Errorf("RenderSARIF() ignored = %+v, want the ignored items", run.Properties.Ignored)
+	}
+
+	type result struct {
+		rule  string
+		level string
+		uri   string
+		line  int
+	}
+
+	want := []result{
+		{rule: ruleMissing, level: "warning", uri: "app/res/values/strings.xml", line: 3},
+		{rule: rulePlaceholder, level: "error", uri: "app/res/values/strings.xml", line: 3},
+		{rule: ruleStale, level: "note", uri: "app/res/values-de/strings.xml"},
+	}
+
+	got := make([]result, 0, len(run.Results))
+	for _, sarifResult := range run.Results {
+		location := sarifResult.Locations[0].PhysicalLocation
+		r := result{rule: sarifResult.RuleID, level: sarifResult.Level, uri: location.ArtifactLocation.URI}
+		if location.Region != nil {
+			r.line = location.Region.StartLine
+		}
+
+		got = append(got, r)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RenderSARIF() results = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,418 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ashutoshgngwr/android-translations/pkg/resources"
+)
+
+// Scan finds the translatable resources in the given Android project directory and
+// reports the resources with missing, incomplete or potentially outdated translations.
+func Scan(projectDir string, opts Options) (*Report, error) {
+	if opts.LocaleAliases == nil {
+		opts.LocaleAliases = resources.DefaultLocaleAliases
+	}
+
+	report := &Report{
+		Options:      opts,
+		Strings:      []StringResource{},
+		ConfigErrors: []string{},
+		Warnings:     []string{},
+		Ignored:      IgnoredItems{Paths: []string{}, Strings: []string{}},
+		ProjectDir:   projectDir,
+		GeneratedAt:  time.Now().UTC(),
+	}
+
+	ignoreStrings, err := compileNamePatterns(opts.IgnoreStrings)
+	if err != nil {
+		return nil, err
+	}
+
+	ignoreIdentical, err := compileNamePatterns(opts.IgnoreIdentical)
+	if err != nil {
+		return nil, err
+	}
+
+	pathFilter := opts.PathFilter
+	if opts.ListIgnored {
+		pathFilter.Skipped = func(relPath string) {
+			report.Ignored.Paths = append(report.Ignored.Paths, relPath)
+			if opts.PathFilter.Skipped != nil {
+				opts.PathFilter.Skipped(relPath)
+			}
+		}
+	}
+
+	valuesFiles, err := resources.FindValuesFiles(projectDir, pathFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	report.ValuesFiles = valuesFiles
+
+	if opts.CheckLocaleSupport {
+		for _, err := range resources.FindUnsupportedLocales(valuesFiles) {
+			report.Warnings = append(report.Warnings, err.Error())
+		}
+	}
+
+	localeStrings, err := resources.FindTranslatableResources(valuesFiles, resources.Options{
+		LocaleAliases: opts.LocaleAliases,
+		LastModified:  opts.OutdatedLocales,
+		Jobs:          opts.Jobs,
+		Cache:         opts.Cache,
+		Warn: func(err error) {
+			report.Warnings = append(report.Warnings, err.Error())
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	ignoredNames := make(map[string]bool)
+	for locale := range localeStrings {
+		for name := range localeStrings[locale] {
+			if matchesAny(ignoreStrings, name) {
+				delete(localeStrings[locale], name)
+				ignoredNames[name] = true
+			}
+		}
+	}
+
+	if opts.ListIgnored {
+		for name := range ignoredNames {
+			report.Ignored.Strings = append(report.Ignored.Strings, name)
+		}
+
+		sort.Strings(report.Ignored.Paths)
+		sort.Strings(report.Ignored.Strings)
+	}
+
+	defaultLocale := resources.DefaultLocale
+	if opts.DefaultLocale != "" && opts.DefaultLocale != resources.DefaultLocale {
+		defaultLocale = resources.NormalizeLocale(opts.DefaultLocale, opts.LocaleAliases)
+		if err := useDefaultLocale(localeStrings, defaultLocale); err != nil {
+			return nil, err
+		}
+	}
+
+	report.LocaleFilters = opts.Locales
+	if len(report.LocaleFilters) == 0 && opts.GradleLocaleFilters {
+		report.LocaleFilters, err = resources.FindGradleLocaleFilters(projectDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(report.LocaleFilters) > 0 {
+		for locale := range localeStrings {
+			if locale != resources.DefaultLocale && !resources.IsLocaleIncluded(locale, report.LocaleFilters, opts.LocaleAliases) {
+				delete(localeStrings, locale)
+			}
+		}
+	}
+
+	defaultStrings, ok := localeStrings[resources.DefaultLocale]
+	if !ok { // shouldn't be true for valid input
+		return nil, errors.New("unable to find string resources for default locale")
+	}
+
+	if defaultLocale != resources.DefaultLocale {
+		report.DefaultLanguage = strings.SplitN(resources.LocaleToLanguageTag(defaultLocale), "-", 2)[0]
+	} else if report.DefaultLanguage, err = resources.FindDefaultLanguage(valuesFiles); err != nil {
+		return nil, err
+	}
+
+	if opts.CheckLocaleConfig {
+		report.ConfigErrors, err = resources.ValidateLocaleConfigs(projectDir, report.DefaultLanguage, localeStrings, opts.LocaleAliases)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var baseStrings map[string]resources.Resource
+	if opts.SinceRef != "" {
+		baseStrings, err = findBaseStrings(projectDir, valuesFiles, defaultLocale, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		defaultStrings = findChangedStrings(baseStrings, defaultStrings)
+	}
+
+	locales := make([]string, 0, len(localeStrings))
+	for locale := range localeStrings {
+		if locale != resources.DefaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+
+	sort.Strings(locales)
+	report.Locales = locales
+	if opts.Started != nil {
+		opts.Started(report)
+	}
+
+	requiredCount := 0
+	sources := &sourceFinder{projectDir: projectDir, modules: map[string]string{}}
+	for _, str := range defaultStrings {
+		if opts.RespectToolsIgnore && str.IsToolsIgnored(resources.ToolsIgnoreAll) {
+			continue
+		}
+
+		required := !opts.RespectToolsIgnore || !str.IsToolsIgnored(resources.ToolsIgnoreMissingTranslation)
+		if required {
+			requiredCount++
+		}
+
+		strResource := StringResource{
+			Name:              str.Name,
+			Type:              str.Type,
+			Value:             str.DisplayValue(),
+			Module:            sources.findModule(str.File),
+			ResourceDir:       resources.FindResourceDir(projectDir, str.File),
+			File:              resources.FindRelativePath(projectDir, str.File),
+			Line:              str.Line,
+			MissingLocales:    []string{},
+			OutdatedLocales:   []string{},
+			MissingQuantities: map[string][]string{},
+			ItemCountMismatch: map[string]int{},
+
+			PlaceholderMismatches: map[string][]string{},
+			Default:               str,
+		}
+
+		for locale := range localeStrings {
+			// an empty translation, e.g. a stub, overrides the default string with a
+			// blank value at runtime, so it is as good as missing.
+			localeStr, ok := localeStrings[locale][str.Name]
+			empty := ok && localeStr.IsEmpty() && !str.IsEmpty()
+			if !ok || empty {
+				if required {
+					strResource.MissingLocales = append(strResource.MissingLocales, locale)
+				}
+
+				if required && empty {
+					strResource.EmptyLocales = append(strResource.EmptyLocales, locale)
+				}
+
+				continue
+			} else if opts.RespectToolsIgnore && localeStr.IsToolsIgnored(resources.ToolsIgnoreAll) {
+				continue
+			} else if opts.OutdatedLocales && localeStr.LastModified.Before(str.LastModified) {
+				strResource.OutdatedLocales = append(strResource.OutdatedLocales, locale)
+			}
+
+			if locale == resources.DefaultLocale || localeStr.Type != str.Type {
+				continue
+			}
+
+			if opts.CheckPlaceholders {
+				if mismatches := resources.FindPlaceholderMismatches(str, localeStr); len(mismatches) > 0 {
+					strResource.PlaceholderMismatches[locale] = mismatches
+				}
+			}
+
+			if opts.CheckIdentical && resources.IsIdenticalTranslation(str, localeStr) &&
+				!matchesAny(ignoreIdentical, str.Name) && !matchesAny(ignoreIdentical, str.DisplayValue()) {
+				strResource.IdenticalLocales = append(strResource.IdenticalLocales, locale)
+			}
+
+			switch str.Type {
+			case resources.TypePlurals:
+				if quantities := resources.FindMissingQuantities(locale, localeStr.Quantities); len(quantities) > 0 {
+					strResource.MissingQuantities[locale] = quantities
+				}
+			case resources.TypeStringArray:
+				if len(localeStr.Items) != len(str.Items) {
+					strResource.ItemCountMismatch[locale] = len(localeStr.Items)
+				}
+			}
+		}
+
+		sort.Strings(strResource.MissingLocales)
+		sort.Strings(strResource.EmptyLocales)
+		sort.Strings(strResource.OutdatedLocales)
+		sort.Strings(strResource.IdenticalLocales)
+		issueCount := len(strResource.MissingLocales) + len(strResource.OutdatedLocales)
+		issueCount += len(strResource.MissingQuantities) + len(strResource.ItemCountMismatch)
+		issueCount += len(strResource.PlaceholderMismatches) + len(strResource.IdenticalLocales)
+		if issueCount > 0 {
+			report.addString(strResource)
+		}
+	}
+
+	report.Coverage = computeCoverage(requiredCount, locales, report.Strings)
+	if opts.CheckStale {
+		for _, str := range findStaleStrings(localeStrings, locales, sources, opts.RespectToolsIgnore) {
+			if _, ok := baseStrings[str.Name]; opts.SinceRef == "" || ok {
+				report.addString(str)
+			}
+		}
+	}
+
+	sort.Sort(stringResources(report.Strings))
+	return report, nil
+}
+
+// addString adds the given string resource to the report and passes it to the 'Found'
+// callback of the report's options.
+func (report *Report) addString(str StringResource) {
+	report.Strings = append(report.Strings, str)
+	if report.Options.Found != nil {
+		report.Options.Found(str)
+	}
+}
+
+// findBaseStrings finds the string resources of the default locale at the merge base
+// of 'opts.SinceRef' and 'HEAD'.
+func findBaseStrings(projectDir string, valuesFiles []string, defaultLocale string, opts Options) (map[string]resources.Resource, error) {
+	mergeBase, err := resources.FindMergeBase(projectDir, opts.SinceRef)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultFiles := make([]string, 0)
+	for _, file := range valuesFiles {
+		if resources.LocaleForValuesFile(file, opts.LocaleAliases) == defaultLocale {
+			defaultFiles = append(defaultFiles, file)
+		}
+	}
+
+	baseResources, err := resources.FindTranslatableResourcesAtRevision(defaultFiles, mergeBase, resources.Options{
+		LocaleAliases: opts.LocaleAliases,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return baseResources[defaultLocale], nil
+}
+
+// useDefaultLocale replaces the default string resources, i.e. the resources in
+// 'values' directories, with the resources of the given locale.
+func useDefaultLocale(localeStrings resources.LocaleResources, locale string) error {
+	strs, ok := localeStrings[locale]
+	if !ok {
+		locales := make([]string, 0, len(localeStrings))
+		for locale := range localeStrings {
+			if locale != resources.DefaultLocale {
+				locales = append(locales, locale)
+			}
+		}
+
+		sort.Strings(locales)
+		const errFmt = "default locale %q has no string resources, available locales: %s"
+		return fmt.Errorf(errFmt, locale, strings.Join(locales, ", "))
+	}
+
+	delete(localeStrings, locale)
+	localeStrings[resources.DefaultLocale] = strs
+	return nil
+}
+
+// findChangedStrings returns the default string resources that were either added or
+// whose values changed since the base string resources.
+func findChangedStrings(baseStrings, defaultStrings map[string]resources.Resource) map[string]resources.Resource {
+	changed := make(map[string]resources.Resource)
+	for name, str := range defaultStrings {
+		if baseStr, ok := baseStrings[name]; !ok || !baseStr.HasSameValue(str) {
+			changed[name] = str
+		}
+	}
+
+	return changed
+}
+
+// findStaleStrings finds the translated resources of the given locales that no longer
+// exist in the default locale. The module of a stale resource is found using its first
+// translation in the order of the locales. If 'respectToolsIgnore' is true, the
+// translations with 'ExtraTranslation' in their 'tools:ignore' attributes are skipped.
+func findStaleStrings(localeStrings resources.LocaleResources, locales []string, sources *sourceFinder, respectToolsIgnore bool) []StringResource {
+	defaultStrings := localeStrings[resources.DefaultLocale]
+	stale := make(map[string]*StringResource)
+	for _, locale := range locales {
+		for name, res := range localeStrings[locale] {
+			if _, ok := defaultStrings[name]; ok {
+				continue
+			} else if respectToolsIgnore && res.IsToolsIgnored(resources.ToolsIgnoreExtraTranslation) {
+				continue
+			}
+
+			if _, ok := stale[name]; !ok {
+				stale[name] = &StringResource{
+					Name:            name,
+					Type:            res.Type,
+					Module:          sources.findModule(res.File),
+					ResourceDir:     resources.FindResourceDir(sources.projectDir, res.File),
+					File:            resources.FindRelativePath(sources.projectDir, res.File),
+					Line:            res.Line,
+					MissingLocales:  []string{},
+					OutdatedLocales: []string{},
+					Stale:           map[string]resources.Resource{},
+				}
+			}
+
+			stale[name].StaleLocales = append(stale[name].StaleLocales, locale)
+			stale[name].Stale[locale] = res
+		}
+	}
+
+	staleStrings := make([]StringResource, 0, len(stale))
+	for _, str := range stale {
+		sort.Strings(str.StaleLocales)
+		staleStrings = append(staleStrings, *str)
+	}
+
+	return staleStrings
+}
+
+// sourceFinder finds the modules of the values files in a project and caches them by
+// the paths of the files.
+type sourceFinder struct {
+	projectDir string
+	modules    map[string]string
+}
+
+// findModule returns the Gradle path of the module containing the given file.
+func (finder *sourceFinder) findModule(file string) string {
+	if _, ok := finder.modules[file]; !ok {
+		finder.modules[file] = resources.FindModule(finder.projectDir, file)
+	}
+
+	return finder.modules[file]
+}
+
+// compileNamePatterns compiles the given regular expressions such that each of them
+// must match the complete name.
+func compileNamePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		regex, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid string name pattern %q: %v", pattern, err)
+		}
+
+		compiled = append(compiled, regex)
+	}
+
+	return compiled, nil
+}
+
+// matchesAny checks if any of the given regular expressions matches the given name.
+func matchesAny(regexes []*regexp.Regexp, name string) bool {
+	for _, regex := range regexes {
+		if regex.MatchString(name) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,142 @@
+package report
+
+import (
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestScanRespectsToolsIgnore(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "res", "values", "strings.xml"), `<resources
+  xmlns:tools="http://schemas.android.com/tools">
+  <string name="title">Title</string>
+  <string name="debug" tools:ignore="MissingTranslation">Debug</string>
+  <string name="internal" tools:ignore="all">Internal</string>
+</resources>`)
+	writeTestFile(t, filepath.Join(dir, "res", "values", "keep.xml"), `<resources
+  xmlns:tools="http://schemas.android.com/tools" tools:ignore="MissingTranslation">
+  <string name="keep">Keep</string>
+</resources>`)
+	writeTestFile(t, filepath.Join(dir, "res", "values-de", "strings.xml"), `<resources
+  xmlns:tools="http://schemas.android.com/tools">
+  <string name="internal" tools:ignore="all">Intern</string>
+  <string name="removed">Entfernt</string>
+  <string name="extra" tools:ignore="ExtraTranslation">Extra</string>
+</resources>`)
+
+	tests := []struct {
+		name        string
+		respect     bool
+		wantMissing map[string][]string
+		wantStale   map[string][]string
+		wantTotal   int
+	}{
+		{
+			name:        "respected",
+			respect:     true,
+			wantMissing: map[string][]string{"title": {"de"}},
+			wantStale:   map[string][]string{"removed": {"de"}},
+			wantTotal:   1,
+		},
+		{
+			name:    "not respected",
+			respect: false,
+			wantMissing: map[string][]string{
+				"debug": {"de"},
+				"keep":  {"de"},
+				"title": {"de"},
+			},
+			wantStale: map[string][]string{"extra": {"de"}, "removed": {"de"}},
+			wantTotal: 4,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report, err := Scan(dir, Options{CheckStale: true, RespectToolsIgnore: test.respect})
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+
+			missing := make(map[string][]string)
+			stale := make(map[string][]string)
+			for _, str := range report.Strings {
+				if len(str.MissingLocales) > 0 {
+					missing[str.Name] = str.MissingLocales
+				}
+
+				if len(str.StaleLocales) > 0 {
+					stale[str.Name] = str.StaleLocales
+				}
+			}
+
+			if !reflect.DeepEqual(missing, test.wantMissing) {
+				t.Errorf("Scan() missing = %v, want %v", missing, test.wantMissing)
+			}
+
+			if !reflect.DeepEqual(stale, test.wantStale) {
+				t.Errorf("Scan() stale = %v, want %v", stale, test.wantStale)
+			}
+
+			if total := report.Coverage.Translated + report.Coverage.Missing; total != test.wantTotal {
+				t.Errorf("Scan() coverage counts %d translations, want %d", total, test.wantTotal)
+			}
+		})
+	}
+}
+
+func TestScanSinceRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+
+	dir := t.TempDir()
+	defaultFile := filepath.Join(dir, "res", "values", "strings.xml")
+	writeTestFile(t, defaultFile, `<resources>
+  <string name="title">Title</string>
+  <string name="body">Body</string>
+</resources>`)
+	writeTestFile(t, filepath.Join(dir, "res", "values-de", "strings.xml"), `<resources>
+  <string name="old">Alt</string>
+</resources>`)
+	runGit(t, dir, "init", "-q")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "base")
+	runGit(t, dir, "branch", "base")
+	writeTestFile(t, defaultFile, `<resources>
+  <string name="title">Title</string>
+  <string name="body">New body</string>
+  <string name="extra">Extra</string>
+</resources>`)
+	runGit(t, dir, "commit", "-q", "-a", "-m", "change")
+
+	report, err := Scan(dir, Options{SinceRef: "base", CheckStale: true})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	names := make([]string, 0, len(report.Strings))
+	for _, str := range report.Strings {
+		names = append(names, str.Name)
+	}
+
+	if want := []string{"body", "extra"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Scan() strings = %q, want %q", names, want)
+	}
+
+	if _, err := Scan(dir, Options{SinceRef: "unknown"}); err == nil {
+		t.Error("Scan() error = nil, want an error for an unknown ref")
+	}
+}
+
+// runGit runs git with the given arguments in 'dir'.
+func runGit(t *testing.T, dir string, args ...string) {
+	args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
@@ -0,0 +1,332 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ashutoshgngwr/android-translations/pkg/resources"
+	"github.com/pkg/errors"
+)
+
+// StubValue declares the values of the generated stubs for missing translations.
+type StubValue string
+
+// values of the generated stubs.
+const (
+	StubValueEmpty   StubValue = "empty"   // stubs without any value
+	StubValueDefault StubValue = "default" // stubs with the default values
+)
+
+// ParseStubValue returns the StubValue with the given name or an error if no such value
+// exists.
+func ParseStubValue(name string) (StubValue, error) {
+	switch value := StubValue(name); value {
+	case StubValueEmpty, StubValueDefault:
+		return value, nil
+	default:
+		return "", fmt.Errorf("unknown stub value %s", name)
+	}
+}
+
+// StubOptions declares the options for writing stubs for missing translations.
+type StubOptions struct {
+	// Value selects the values of the stubs. If empty, StubValueEmpty is used.
+	Value StubValue
+
+	// Comment, if not empty, is added as an XML comment before each stub, e.g.
+	// 'TODO: translate'.
+	Comment string
+}
+
+// indentRegex matches the indentation of the first indented tag in a values file.
+var indentRegex = regexp.MustCompile(`(?m)^([ \t]+)<`)
+
+// WriteStubs appends stubs for the missing translations in the report to the values
+// files of their locales. The stubs of a locale are written to the file with the same
+// name as the default resource's file in the same resource directory, e.g.
+// 'res/values-de/strings.xml' for 'res/values/strings.xml'. If the locale has no values
+// directory in the resource directory, it is created using the suffix of the locale's
+// values directories elsewhere in the project. The translations that exist but are
+// empty, e.g. the stubs written earlier, are left as is. It returns the paths of the
+// written files.
+func WriteStubs(report *Report, opts StubOptions) ([]string, error) {
+	localeDirs := findLocaleDirs(report.ValuesFiles, report.Options.LocaleAliases)
+	stubs := make(map[string][]resources.Resource)
+	for _, str := range report.Strings {
+		if str.IsStale() || str.Default.File == "" {
+			continue
+		}
+
+		resDir := filepath.Dir(filepath.Dir(str.Default.File))
+		empty := make(map[string]bool, len(str.EmptyLocales))
+		for _, locale := range str.EmptyLocales {
+			empty[locale] = true
+		}
+
+		for _, locale := range str.MissingLocales {
+			if empty[locale] {
+				continue
+			}
+
+			dir, ok := localeDirs[locale][resDir]
+			if !ok {
+				dir, ok = findLocaleDirSuffix(localeDirs[locale], resDir)
+			}
+
+			if !ok { // shouldn't be true since the locale was found in values files
+				continue
+			}
+
+			file := filepath.Join(dir, filepath.Base(str.Default.File))
+			stubs[file] = append(stubs[file], stubResource(locale, str.Default, opts.Value))
+		}
+	}
+
+	files := make([]string, 0, len(stubs))
+	for file := range stubs {
+		files = append(files, file)
+	}
+
+	sort.Strings(files)
+	for _, file := range files {
+		sort.Slice(stubs[file], func(i, j int) bool { return stubs[file][i].Name < stubs[file][j].Name })
+		if err := appendStubs(file, stubs[file], opts.Comment); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// findLocaleDirs maps the locales of the given values files to their values directories
+// keyed by their resource directories.
+func findLocaleDirs(valuesFiles []string, aliases map[string]string) map[string]map[string]string {
+	localeDirs := make(map[string]map[string]string)
+	for _, file := range valuesFiles {
+		locale := resources.LocaleForValuesFile(file, aliases)
+		if _, ok := localeDirs[locale]; !ok {
+			localeDirs[locale] = make(map[string]string)
+		}
+
+		// prefer the directories without any other qualifiers, e.g. 'values-de' over
+		// 'values-de-night'.
+		dir := filepath.Dir(file)
+		resDir := filepath.Dir(dir)
+		if existing, ok := localeDirs[locale][resDir]; !ok || len(filepath.Base(dir)) < len(filepath.Base(existing)) {
+			localeDirs[locale][resDir] = dir
+		}
+	}
+
+	return localeDirs
+}
+
+// findLocaleDirSuffix returns the path of the values directory in 'resDir' with the
+// same name as the first, in sorted order, of the given values directories.
+func findLocaleDirSuffix(dirs map[string]string, resDir string) (string, bool) {
+	names := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		names = append(names, filepath.Base(dir))
+	}
+
+	if len(names) == 0 {
+		return "", false
+	}
+
+	sort.Strings(names)
+	return filepath.Join(resDir, names[0]), true
+}
+
+// stubResource returns the stub of the given default resource for a locale. For
+// 'plurals', it contains the quantities required by the locale. The stubs with the
+// default values keep their markup, e.g. '<xliff:g>' tags.
+func stubResource(locale string, defaultRes resources.Resource, value StubValue) resources.Resource {
+	stub := resources.Resource{Type: defaultRes.Type, Name: defaultRes.Name}
+	switch defaultRes.Type {
+	case resources.TypeStringArray:
+		stub.Items = make([]string, len(defaultRes.Items))
+		if value == StubValueDefault {
+			copy(stub.Items, defaultRes.Items)
+			stub.Raw.Items = defaultRes.Raw.Items
+		}
+	case resources.TypePlurals:
+		stub.Quantities = make(map[string]string)
+		stub.Raw.Quantities = make(map[string]string)
+		for _, quantity := range resources.FindMissingQuantities(locale, nil) {
+			stub.Quantities[quantity] = ""
+			if value != StubValueDefault {
+				continue
+			}
+
+			defaultQuantity := quantity
+			if _, ok := defaultRes.Quantities[quantity]; !ok {
+				defaultQuantity = "other"
+			}
+
+			stub.Quantities[quantity] = defaultRes.Quantities[defaultQuantity]
+			if raw, ok := defaultRes.Raw.Quantities[defaultQuantity]; ok {
+				stub.Raw.Quantities[quantity] = raw
+			}
+		}
+	default:
+		if value == StubValueDefault {
+			stub.Value = defaultRes.Value
+			stub.Raw.Value = defaultRes.Raw.Value
+		}
+	}
+
+	return stub
+}
+
+// appendStubs inserts the XML representation of the given stubs before the closing
+// 'resources' tag of the values file at 'file' using the file's indentation. If the
+// file doesn't exist, it is created.
+func appendStubs(file string, stubs []resources.Resource, comment string) error {
+	content, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		content = []byte("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<resources>\n</resources>\n")
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return errors.Wrapf(err, "unable to create directory %s", filepath.Dir(file))
+		}
+	} else if err != nil {
+		return errors.Wrapf(err, "unable to read file at %s", file)
+	}
+
+	// the markup of the stubs may use the 'xliff' prefix, e.g. '<xliff:g>' tags.
+	if stubsUseXLIFF(stubs) && !bytes.Contains(content, []byte("xmlns:xliff=")) {
+		start := bytes.Index(content, []byte("<resources"))
+		if start < 0 {
+			return fmt.Errorf("unable to find opening resources tag in %s", file)
+		}
+
+		start += len("<resources")
+		declaration := []byte(` xmlns:xliff="` + xliffNamespace + `"`)
+		content = bytes.Join([][]byte{content[:start], declaration, content[start:]}, nil)
+	}
+
+	end := bytes.LastIndex(content, []byte("</resources>"))
+	if end < 0 {
+		return fmt.Errorf("unable to find closing resources tag in %s", file)
+	}
+
+	indent := "    "
+	if match := indentRegex.FindSubmatch(content); match != nil {
+		indent = string(match[1])
+	}
+
+	// insert the stubs at the start of the closing tag's line if it's only preceded by
+	// whitespace.
+	lineStart := bytes.LastIndexByte(content[:end], '\n') + 1
+	var stubContent bytes.Buffer
+	if len(bytes.TrimSpace(content[lineStart:end])) > 0 {
+		lineStart = end
+		stubContent.WriteString("\n")
+	}
+
+	for _, stub := range stubs {
+		if comment != "" {
+			fmt.Fprintf(&stubContent, "%s<!-- %s -->\n", indent, strings.ReplaceAll(comment, "--", "- -"))
+		}
+
+		writeStubXML(&stubContent, stub, indent)
+	}
+
+	output := make([]byte, 0, len(content)+stubContent.Len())
+	output = append(output, content[:lineStart]...)
+	output = append(output, stubContent.Bytes()...)
+	output = append(output, content[lineStart:]...)
+	if err := ioutil.WriteFile(file, output, 0644); err != nil {
+		return errors.Wrapf(err, "unable to write file at %s", file)
+	}
+
+	return nil
+}
+
+// xliffNamespace is the namespace of the 'xliff:' tags in Android values files.
+const xliffNamespace = "urn:oasis:names:tc:xliff:document:1.2"
+
+// stubsUseXLIFF checks if the markup of any of the given stubs contains 'xliff:' tags.
+func stubsUseXLIFF(stubs []resources.Resource) bool {
+	for _, stub := range stubs {
+		raw := append([]string{stub.Raw.Value}, stub.Raw.Items...)
+		for _, value := range stub.Raw.Quantities {
+			raw = append(raw, value)
+		}
+
+		for _, value := range raw {
+			if strings.Contains(value, "<xliff:") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// writeStubXML writes the XML representation of the given stub to 'buf'. The raw values
+// of the stub are written as is, and the others are escaped.
+func writeStubXML(buf *bytes.Buffer, stub resources.Resource, indent string) {
+	switch stub.Type {
+	case resources.TypeStringArray:
+		fmt.Fprintf(buf, "%s<string-array name=\"%s\">\n", indent, escapeXML(stub.Name))
+		for i, item := range stub.Items {
+			if i < len(stub.Raw.Items) {
+				item = stub.Raw.Items[i]
+			} else {
+				item = escapeXML(item)
+			}
+
+			fmt.Fprintf(buf, "%s%s<item>%s</item>\n", indent, indent, item)
+		}
+
+		fmt.Fprintf(buf, "%s</string-array>\n", indent)
+	case resources.TypePlurals:
+		quantities := make([]string, 0, len(stub.Quantities))
+		for quantity := range stub.Quantities {
+			quantities = append(quantities, quantity)
+		}
+
+		sortQuantities(quantities)
+		fmt.Fprintf(buf, "%s<plurals name=\"%s\">\n", indent, escapeXML(stub.Name))
+		for _, quantity := range quantities {
+			value, ok := stub.Raw.Quantities[quantity]
+			if !ok {
+				value = escapeXML(stub.Quantities[quantity])
+			}
+
+			fmt.Fprintf(buf, "%s%s<item quantity=\"%s\">%s</item>\n", indent, indent, quantity, value)
+		}
+
+		fmt.Fprintf(buf, "%s</plurals>\n", indent)
+	default:
+		value := stub.Raw.Value
+		if value == "" {
+			value = escapeXML(stub.Value)
+		}
+
+		fmt.Fprintf(buf, "%s<string name=\"%s\">%s</string>\n", indent, escapeXML(stub.Name), value)
+	}
+}
+
+// quantityOrder declares the order of the plural quantities in the generated stubs.
+var quantityOrder = map[string]int{"zero": 0, "one": 1, "two": 2, "few": 3, "many": 4, "other": 5}
+
+// sortQuantities sorts the given plural quantities in the CLDR order.
+func sortQuantities(quantities []string) {
+	sort.Slice(quantities, func(i, j int) bool {
+		return quantityOrder[quantities[i]] < quantityOrder[quantities[j]]
+	})
+}
+
+// escapeXML escapes the special characters of XML in 's'.
+func escapeXML(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s)) // writing to bytes.Buffer never fails
+	return buf.String()
+}
@@ -0,0 +1,220 @@
+package report
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ashutoshgngwr/android-translations/pkg/resources"
+)
+
+func TestAppendStubs(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string // content of the existing file, if any
+		stubs   []resources.Resource
+		comment string
+		want    string
+	}{
+		{
+			name:  "new file",
+			stubs: []resources.Resource{{Type: resources.TypeString, Name: "title"}},
+			want: `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <string name="title"></string>
+</resources>
+`,
+		},
+		{
+			name: "existing file",
+			content: `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+  <string name="title">Titel</string>
+</resources>
+`,
+			stubs: []resources.Resource{{Type: resources.TypeString, Name: "body", Value: "Fish & <Chips>"}},
+			want: `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+  <string name="title">Titel</string>
+  <string name="body">Fish &amp; &lt;Chips&gt;</string>
+</resources>
+`,
+		},
+		{
+			name:    "closing tag after content",
+			content: "<resources>\n\t<string name=\"title\">Titel</string></resources>",
+			stubs:   []resources.Resource{{Type: resources.TypeString, Name: "body"}},
+			want:    "<resources>\n\t<string name=\"title\">Titel</string>\n\t<string name=\"body\"></string>\n</resources>",
+		},
+		{
+			name:    "comment",
+			content: "<resources>\n</resources>\n",
+			stubs:   []resources.Resource{{Type: resources.TypeString, Name: "title"}},
+			comment: "TODO -- translate",
+			want:    "<resources>\n    <!-- TODO - - translate -->\n    <string name=\"title\"></string>\n</resources>\n",
+		},
+		{
+			name:    "plurals and string-array",
+			content: "<resources>\n</resources>\n",
+			stubs: []resources.Resource{
+				{Type: resources.TypePlurals, Name: "items", Quantities: map[string]string{"other": "", "many": "", "one": ""}},
+				{Type: resources.TypeStringArray, Name: "colors", Items: []string{"Red", "Blue"}},
+			},
+			want: `<resources>
+    <plurals name="items">
+        <item quantity="one"></item>
+        <item quantity="many"></item>
+        <item quantity="other"></item>
+    </plurals>
+    <string-array name="colors">
+        <item>Red</item>
+        <item>Blue</item>
+    </string-array>
+</resources>
+`,
+		},
+		{
+			name: "markup",
+			stubs: []resources.Resource{{
+				Type:  resources.TypeString,
+				Name:  "tap",
+				Value: "Tap here %1$s",
+				Raw:   resources.RawValues{Value: `Tap <b>here</b> <xliff:g id="name">%1$s</xliff:g>`},
+			}},
+			want: `<?xml version="1.0" encoding="utf-8"?>
+<resources xmlns:xliff="urn:oasis:names:tc:xliff:document:1.2">
+    <string name="tap">Tap <b>here</b> <xliff:g id="name">%1$s</xliff:g></string>
+</resources>
+`,
+		},
+		{
+			name:    "markup with xliff namespace",
+			content: "<resources xmlns:xliff=\"urn:oasis:names:tc:xliff:document:1.2\">\n</resources>\n",
+			stubs: []resources.Resource{{
+				Type:  resources.TypeStringArray,
+				Name:  "colors",
+				Items: []string{"Red", "Blue"},
+				Raw:   resources.RawValues{Items: []string{"<xliff:g>Red</xliff:g>", "<i>Blue</i>"}},
+			}},
+			want: `<resources xmlns:xliff="urn:oasis:names:tc:xliff:document:1.2">
+    <string-array name="colors">
+        <item><xliff:g>Red</xliff:g></item>
+        <item><i>Blue</i></item>
+    </string-array>
+</resources>
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "values-de", "strings.xml")
+			if test.content != "" {
+				writeTestFile(t, file, test.content)
+			}
+
+			if err := appendStubs(file, test.stubs, test.comment); err != nil {
+				t.Fatalf("appendStubs() error = %v", err)
+			}
+
+			content, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(content) != test.want {
+				t.Errorf("appendStubs() wrote\n%s\nwant\n%s", content, test.want)
+			}
+		})
+	}
+}
+
+func TestStubResource(t *testing.T) {
+	defaultString := resources.Resource{
+		Type:  resources.TypeString,
+		Name:  "tap",
+		Value: "Tap here",
+		Raw:   resources.RawValues{Value: "Tap <b>here</b>"},
+	}
+
+	defaultPlurals := resources.Resource{
+		Type:       resources.TypePlurals,
+		Name:       "items",
+		Quantities: map[string]string{"one": "One item", "other": "%d items"},
+		Raw: resources.RawValues{Quantities: map[string]string{
+			"one":   "One item",
+			"other": `<xliff:g id="count">%d</xliff:g> items`,
+		}},
+	}
+
+	tests := []struct {
+		name       string
+		locale     string
+		defaultRes resources.Resource
+		value      StubValue
+		want       resources.Resource
+	}{
+		{
+			name:       "empty",
+			locale:     "de",
+			defaultRes: defaultString,
+			value:      StubValueEmpty,
+			want:       resources.Resource{Type: resources.TypeString, Name: "tap"},
+		},
+		{
+			name:       "default with markup",
+			locale:     "de",
+			defaultRes: defaultString,
+			value:      StubValueDefault,
+			want:       defaultString,
+		},
+		{
+			name:       "plurals with missing quantities",
+			locale:     "ru",
+			defaultRes: defaultPlurals,
+			value:      StubValueDefault,
+			want: resources.Resource{
+				Type: resources.TypePlurals,
+				Name: "items",
+				Quantities: map[string]string{
+					"one": "One item", "few": "%d items", "many": "%d items", "other": "%d items",
+				},
+				Raw: resources.RawValues{Quantities: map[string]string{
+					"one":   "One item",
+					"few":   `<xliff:g id="count">%d</xliff:g> items`,
+					"many":  `<xliff:g id="count">%d</xliff:g> items`,
+					"other": `<xliff:g id="count">%d</xliff:g> items`,
+				}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := stubResource(test.locale, test.defaultRes, test.value); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("stubResource() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAppendStubsWithoutClosingTag(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "strings.xml")
+	writeTestFile(t, file, "<resources>\n")
+	if err := appendStubs(file, []resources.Resource{{Type: resources.TypeString, Name: "title"}}, ""); err == nil {
+		t.Error("appendStubs() error = nil, want an error")
+	}
+}
+
+// writeTestFile writes the given content to the file, creating its parent directory.
+func writeTestFile(t *testing.T, file, content string) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,83 @@
+package report
+
+import (
+	"fmt"
+
+	"github.com/ashutoshgngwr/android-translations/pkg/resources"
+)
+
+// Thresholds declares the conditions that a report must meet to pass the CI gating.
+type Thresholds struct {
+	// FailOnMissing, if true, fails the report if any translation is missing or
+	// any 'plurals' translation is missing a required quantity.
+	FailOnMissing bool
+
+	// FailOnLocaleConfig, if true, fails the report if the locales declared in the
+	// app's 'localeConfig' don't match the translations. See Report.ConfigErrors.
+	FailOnLocaleConfig bool
+
+	// FailOnPlaceholderMismatch, if true, fails the report if the placeholders of any
+	// translation don't match its default string.
+	FailOnPlaceholderMismatch bool
+
+	// MinCoveragePercent is the minimum overall coverage percentage.
+	MinCoveragePercent float64
+
+	// MinLocaleCoveragePercent is the minimum coverage percentage of each locale.
+	MinLocaleCoveragePercent float64
+
+	// RequiredLocales lists the locales that must be completely translated. They can
+	// be 'values-' suffixes or BCP 47 language tags, and are resolved using the
+	// locale aliases of the report.
+	RequiredLocales []string
+}
+
+// CheckThresholds returns a short description of each condition in the given thresholds
+// that the report fails to meet.
+func (report *Report) CheckThresholds(thresholds Thresholds) []string {
+	failures := make([]string, 0)
+	if thresholds.FailOnMissing {
+		incomplete := 0
+		for _, str := range report.Strings {
+			if len(str.MissingLocales)+len(str.MissingQuantities) > 0 {
+				incomplete++
+			}
+		}
+
+		if incomplete > 0 {
+			failures = append(failures, fmt.Sprintf("%d strings have missing translations", incomplete))
+		}
+	}
+
+	if thresholds.FailOnLocaleConfig && len(report.ConfigErrors) > 0 {
+		failures = append(failures, fmt.Sprintf("found %d locale config errors", len(report.ConfigErrors)))
+	}
+
+	if mismatchCount := report.PlaceholderMismatchCount(); thresholds.FailOnPlaceholderMismatch && mismatchCount > 0 {
+		failures = append(failures, fmt.Sprintf("%d translations have mismatching placeholders", mismatchCount))
+	}
+
+	if report.Coverage.Percent < thresholds.MinCoveragePercent {
+		const failureFmt = "overall coverage %.2f%% is below %.2f%%"
+		failures = append(failures, fmt.Sprintf(failureFmt, report.Coverage.Percent, thresholds.MinCoveragePercent))
+	}
+
+	for _, localeCoverage := range report.Coverage.Locales {
+		if localeCoverage.Percent < thresholds.MinLocaleCoveragePercent {
+			const failureFmt = "coverage of locale %q %.2f%% is below %.2f%%"
+			failures = append(failures, fmt.Sprintf(failureFmt, localeCoverage.Locale, localeCoverage.Percent, thresholds.MinLocaleCoveragePercent))
+		}
+	}
+
+	for _, locale := range thresholds.RequiredLocales {
+		normalized := resources.NormalizeLocale(locale, report.Options.LocaleAliases)
+		if localeCoverage, ok := report.Coverage.LocaleCoverage(normalized); !ok {
+			failures = append(failures, fmt.Sprintf("required locale %q has no translations", locale))
+		} else if localeCoverage.Missing > 0 {
+			const failureFmt = "required locale %q is missing %d translations"
+			failures = append(failures, fmt.Sprintf(failureFmt, locale, localeCoverage.Missing))
+		}
+	}
+
+	return failures
+}
@@ -0,0 +1,192 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+
+	"github.com/ashutoshgngwr/android-translations/pkg/resources"
+	"github.com/pkg/errors"
+)
+
+// XLIFF versions supported by RenderXLIFF.
+const (
+	XLIFFVersion12 = "1.2"
+	XLIFFVersion20 = "2.0"
+)
+
+// xliff12 declares data structure for marshalling XLIFF 1.2 documents.
+type xliff12 struct {
+	XMLName xml.Name `xml:"urn:oasis:names:tc:xliff:document:1.2 xliff"`
+	Version string   `xml:"version,attr"`
+	File    struct {
+		Original       string             `xml:"original,attr"`
+		SourceLanguage string             `xml:"source-language,attr"`
+		TargetLanguage string             `xml:"target-language,attr"`
+		Datatype       string             `xml:"datatype,attr"`
+		TransUnits     []xliff12TransUnit `xml:"body>trans-unit"`
+	} `xml:"file"`
+}
+
+// xliff12TransUnit declares data structure for marshalling 'trans-unit' tags of XLIFF
+// 1.2 documents.
+type xliff12TransUnit struct {
+	ID      string `xml:"id,attr"`
+	ResName string `xml:"resname,attr"`
+	Source  string `xml:"source"`
+	Note    string `xml:"note,omitempty"`
+}
+
+// xliff20 declares data structure for marshalling XLIFF 2.0 documents.
+type xliff20 struct {
+	XMLName xml.Name `xml:"urn:oasis:names:tc:xliff:document:2.0 xliff"`
+	Version string   `xml:"version,attr"`
+	SrcLang string   `xml:"srcLang,attr"`
+	TrgLang string   `xml:"trgLang,attr"`
+	File    struct {
+		ID    string        `xml:"id,attr"`
+		Units []xliff20Unit `xml:"unit"`
+	} `xml:"file"`
+}
+
+// xliff20Unit declares data structure for marshalling 'unit' tags of XLIFF 2.0 documents.
+type xliff20Unit struct {
+	ID     string        `xml:"id,attr"`
+	Name   string        `xml:"name,attr"`
+	Notes  *xliff20Notes `xml:"notes,omitempty"`
+	Source string        `xml:"segment>source"`
+}
+
+// xliff20Notes declares data structure for marshalling 'notes' tags of XLIFF 2.0
+// documents.
+type xliff20Notes struct {
+	Notes []string `xml:"note"`
+}
+
+// xliffUnit declares a single translatable text unit independent of XLIFF version.
+type xliffUnit struct {
+	ID     string
+	Name   string
+	Source string
+	Note   string
+}
+
+// RenderXLIFF renders the missing translations of each locale in the report as an XLIFF
+// document of the given version. A text unit is rendered for each missing string, each
+// item of a missing 'string-array' and each missing quantity of a 'plurals' with its
+// default value as the source. It returns the documents keyed by their locales.
+func RenderXLIFF(report *Report, version string) (map[string]string, error) {
+	documents := make(map[string]string)
+	for locale, units := range findXLIFFUnits(report) {
+		var v interface{}
+		switch version {
+		case XLIFFVersion12:
+			doc := &xliff12{Version: version}
+			doc.File.Original = "strings.xml"
+			doc.File.SourceLanguage = report.DefaultLanguage
+			doc.File.TargetLanguage = resources.LocaleToLanguageTag(locale)
+			doc.File.Datatype = "xml"
+			for _, unit := range units {
+				doc.File.TransUnits = append(doc.File.TransUnits, xliff12TransUnit{
+					ID:      unit.ID,
+					ResName: unit.Name,
+					Source:  unit.Source,
+					Note:    unit.Note,
+				})
+			}
+
+			v = doc
+		case XLIFFVersion20:
+			doc := &xliff20{
+				Version: version,
+				SrcLang: report.DefaultLanguage,
+				TrgLang: resources.LocaleToLanguageTag(locale),
+			}
+
+			doc.File.ID = "strings"
+			for _, unit := range units {
+				xmlUnit := xliff20Unit{ID: unit.ID, Name: unit.Name, Source: unit.Source}
+				if unit.Note != "" {
+					xmlUnit.Notes = &xliff20Notes{Notes: []string{unit.Note}}
+				}
+
+				doc.File.Units = append(doc.File.Units, xmlUnit)
+			}
+
+			v = doc
+		default:
+			return nil, fmt.Errorf("unsupported XLIFF version %s", version)
+		}
+
+		content, err := xml.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal content as XLIFF")
+		}
+
+		documents[locale] = xml.Header + string(content) + "\n"
+	}
+
+	return documents, nil
+}
+
+// findXLIFFUnits returns the text units of the missing translations in the report
+// keyed by their locales. The ids of the units are prefixed with the projects of their
+// strings in merged reports, e.g. 'app/title', since the names of the strings are only
+// unique within a project.
+func findXLIFFUnits(report *Report) map[string][]xliffUnit {
+	units := make(map[string][]xliffUnit)
+	for _, str := range report.Strings {
+		res := str.Default
+		id := prefixPath(str.Project, res.Name)
+
+		for _, locale := range str.MissingLocales {
+			switch res.Type {
+			case resources.TypeString:
+				units[locale] = append(units[locale], xliffUnit{ID: id, Name: res.Name, Source: res.Value})
+			case resources.TypeStringArray:
+				for i, item := range res.Items {
+					unit := xliffUnit{ID: fmt.Sprintf("%s:%d", id, i), Name: res.Name, Source: item}
+					unit.Note = fmt.Sprintf("string-array item: %d", i)
+					units[locale] = append(units[locale], unit)
+				}
+			case resources.TypePlurals:
+				quantities := resources.FindMissingQuantities(locale, map[string]string{})
+				units[locale] = append(units[locale], pluralsXLIFFUnits(id, res, quantities)...)
+			}
+		}
+
+		locales := make([]string, 0, len(str.MissingQuantities))
+		for locale := range str.MissingQuantities {
+			locales = append(locales, locale)
+		}
+
+		sort.Strings(locales)
+		for _, locale := range locales {
+			units[locale] = append(units[locale], pluralsXLIFFUnits(id, res, str.MissingQuantities[locale])...)
+		}
+	}
+
+	return units
+}
+
+// pluralsXLIFFUnits returns the text units for the given quantities of a 'plurals'
+// resource, using 'id' as the prefix of their ids. If the default resource doesn't
+// define a quantity, the value of its 'other' quantity is used as the source.
+func pluralsXLIFFUnits(id string, res resources.Resource, quantities []string) []xliffUnit {
+	units := make([]xliffUnit, 0, len(quantities))
+	for _, quantity := range quantities {
+		source, ok := res.Quantities[quantity]
+		if !ok {
+			source = res.Quantities["other"]
+		}
+
+		units = append(units, xliffUnit{
+			ID:     fmt.Sprintf("%s:%s", id, quantity),
+			Name:   res.Name,
+			Source: source,
+			Note:   fmt.Sprintf("plurals quantity: %s", quantity),
+		})
+	}
+
+	return units
+}
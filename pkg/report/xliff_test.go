@@ -0,0 +1,64 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ashutoshgngwr/android-translations/pkg/resources"
+)
+
+func TestFindXLIFFUnits(t *testing.T) {
+	title := resources.Resource{Type: resources.TypeString, Name: "title", Value: "Title"}
+	colors := resources.Resource{Type: resources.TypeStringArray, Name: "colors", Items: []string{"Red", "Blue"}}
+	items := resources.Resource{
+		Type:       resources.TypePlurals,
+		Name:       "items",
+		Quantities: map[string]string{"one": "One item", "other": "%d items"},
+	}
+
+	report := &Report{
+		Strings: []StringResource{
+			{Name: "title", Default: title, MissingLocales: []string{"de"}},
+			{Name: "colors", Default: colors, MissingLocales: []string{"de"}},
+			{Name: "items", Default: items, MissingQuantities: map[string][]string{"ru": {"few", "many"}}},
+		},
+	}
+
+	want := map[string][]xliffUnit{
+		"de": {
+			{ID: "title", Name: "title", Source: "Title"},
+			{ID: "colors:0", Name: "colors", Source: "Red", Note: "string-array item: 0"},
+			{ID: "colors:1", Name: "colors", Source: "Blue", Note: "string-array item: 1"},
+		},
+		"ru": {
+			{ID: "items:few", Name: "items", Source: "%d items", Note: "plurals quantity: few"},
+			{ID: "items:many", Name: "items", Source: "%d items", Note: "plurals quantity: many"},
+		},
+	}
+
+	if got := findXLIFFUnits(report); !reflect.DeepEqual(got, want) {
+		t.Errorf("findXLIFFUnits() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindXLIFFUnitsOfMergedReport(t *testing.T) {
+	title := resources.Resource{Type: resources.TypeString, Name: "title", Value: "Title"}
+	items := resources.Resource{Type: resources.TypePlurals, Name: "items", Quantities: map[string]string{"other": "Items"}}
+	reports := []*Report{
+		{Strings: []StringResource{
+			{Name: "title", Default: title, MissingLocales: []string{"de"}},
+			{Name: "items", Default: items, MissingQuantities: map[string][]string{"de": {"one"}}},
+		}},
+		{Strings: []StringResource{{Name: "title", Default: title, MissingLocales: []string{"de"}}}},
+	}
+
+	ids := make([]string, 0)
+	for _, unit := range findXLIFFUnits(MergeReports([]string{"apps/one", "./apps/two"}, reports))["de"] {
+		ids = append(ids, unit.ID)
+	}
+
+	want := []string{"apps/one/items:one", "apps/one/title", "apps/two/title"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("findXLIFFUnits() ids = %q, want %q", ids, want)
+	}
+}
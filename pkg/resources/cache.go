@@ -0,0 +1,48 @@
+package resources
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// ParseCache caches the resources parsed from values files by their paths, e.g. to scan
+// a project again without parsing its unchanged files. A cached file is parsed again if
+// its modification time or size changes. It is safe for concurrent use.
+type ParseCache struct {
+	mutex   sync.Mutex
+	entries map[string]parseCacheEntry
+}
+
+// parseCacheEntry declares the resources parsed from a values file and the state of the
+// file when it was parsed.
+type parseCacheEntry struct {
+	modTime   time.Time
+	size      int64
+	resources LocaleResources
+}
+
+// NewParseCache creates an empty ParseCache.
+func NewParseCache() *ParseCache {
+	return &ParseCache{entries: map[string]parseCacheEntry{}}
+}
+
+// get returns the cached resources of the given file if the file didn't change since
+// it was parsed.
+func (cache *ParseCache) get(file string, info os.FileInfo) (LocaleResources, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	entry, ok := cache.entries[file]
+	if !ok || !entry.modTime.Equal(info.ModTime()) || entry.size != info.Size() {
+		return nil, false
+	}
+
+	return entry.resources, true
+}
+
+// put caches the resources parsed from the given file.
+func (cache *ParseCache) put(file string, info os.FileInfo, resources LocaleResources) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.entries[file] = parseCacheEntry{modTime: info.ModTime(), size: info.Size(), resources: resources}
+}
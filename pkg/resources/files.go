@@ -0,0 +1,211 @@
+package resources
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// doNotTranslateFileName as recognised by the Android Developer Tools
+// http://tools.android.com/recent/non-translatablestrings
+const doNotTranslateFileName = "donottranslate.xml"
+
+// FindValuesFiles finds XML files in 'path/**/*/values*'. This function should be
+// compatible with cases where multiple resource directories are in use. It skips the
+// paths excluded by the given filter and the values files that it doesn't include.
+func FindValuesFiles(path string, filter PathFilter) ([]string, error) {
+	compiledFilter, err := filter.compile()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid path filter")
+	}
+
+	return findFiles(path, func(filePath string) bool {
+		relPath, err := filepath.Rel(path, filePath)
+		if err != nil {
+			return false
+		}
+
+		if compiledFilter.isExcluded(relPath) {
+			compiledFilter.skip(relPath)
+			return false
+		}
+
+		return true
+	}, func(filePath string) bool {
+		if !isValuesFile(filePath) {
+			return false
+		}
+
+		relPath, err := filepath.Rel(path, filePath)
+		if err != nil {
+			return false
+		}
+
+		if !compiledFilter.isIncluded(relPath) {
+			compiledFilter.skip(relPath)
+			return false
+		}
+
+		return true
+	})
+}
+
+// FindModule returns the Gradle path, e.g. ':app' or ':feature:login', of the module
+// containing the given file in the project at 'projectDir'. A module is the nearest
+// ancestor directory of the file with a 'build.gradle' or 'build.gradle.kts' file. If
+// there is no such directory, the parent of the 'src' directory in the file's path is
+// used. It returns ':' for the root project.
+func FindModule(projectDir, file string) string {
+	for dir := filepath.Dir(file); ; dir = filepath.Dir(dir) {
+		relDir, err := filepath.Rel(projectDir, dir)
+		if err != nil || strings.HasPrefix(relDir, "..") {
+			break
+		}
+
+		if isModuleDir(dir) {
+			return toGradlePath(relDir)
+		}
+
+		if relDir == "." {
+			break
+		}
+	}
+
+	relPath, err := filepath.Rel(projectDir, file)
+	if err != nil {
+		return ":"
+	}
+
+	parts := strings.Split(filepath.ToSlash(relPath), "/")
+	for i, part := range parts {
+		if part == "src" {
+			return toGradlePath(strings.Join(parts[:i], "/"))
+		}
+	}
+
+	return ":"
+}
+
+// FindResourceDir returns the path of the resource directory, e.g. 'app/src/main/res',
+// containing the given values file relative to 'projectDir'.
+func FindResourceDir(projectDir, file string) string {
+	resDir := filepath.Dir(filepath.Dir(file))
+	if relDir, err := filepath.Rel(projectDir, resDir); err == nil {
+		resDir = relDir
+	}
+
+	return filepath.ToSlash(resDir)
+}
+
+// FindRelativePath returns the path of the given file relative to the project directory
+// using '/' as separator, e.g. 'app/src/main/res/values/strings.xml'.
+func FindRelativePath(projectDir, file string) string {
+	if relPath, err := filepath.Rel(projectDir, file); err == nil {
+		file = relPath
+	}
+
+	return filepath.ToSlash(file)
+}
+
+// countQualifiers returns the number of '-' separated qualifiers of the values directory
+// containing the given file.
+func countQualifiers(file string) int {
+	return strings.Count(filepath.Base(filepath.Dir(file)), "-")
+}
+
+// isModuleDir checks if the given directory contains a Gradle build script.
+func isModuleDir(dir string) bool {
+	for _, name := range []string{"build.gradle", "build.gradle.kts"} {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil && !info.IsDir() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toGradlePath converts a directory path relative to the root project to its Gradle
+// path.
+func toGradlePath(relDir string) string {
+	relDir = strings.Trim(filepath.ToSlash(relDir), "/")
+	if relDir == "." {
+		relDir = ""
+	}
+
+	return ":" + strings.ReplaceAll(relDir, "/", ":")
+}
+
+// findFiles recursively finds the files in 'path' for which 'match' returns true. It
+// skips the files and directories that are ignored by 'git' or for which 'visit'
+// returns false.
+func findFiles(path string, visit, match func(string) bool) ([]string, error) {
+	checker := newGitIgnoreChecker(path)
+	defer checker.close()
+	return walkFiles(path, checker, visit, match)
+}
+
+// walkFiles implements findFiles using the given checker for the ignored paths.
+func walkFiles(path string, checker *gitIgnoreChecker, visit, match func(string) bool) ([]string, error) {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read directory %s", path)
+	}
+
+	matches := make([]string, 0)
+	for _, file := range files {
+		filePath := filepath.Join(path, file.Name())
+		if !visit(filePath) || checker.isIgnored(filePath) {
+			continue
+		}
+
+		if file.IsDir() {
+			moreMatches, err := walkFiles(filePath, checker, visit, match)
+			if err != nil {
+				return nil, err
+			}
+
+			matches = append(matches, moreMatches...)
+		} else {
+			if match(filePath) {
+				matches = append(matches, filePath)
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// isValuesFile checks the prefix on the parent of the given path. It also checks
+// the file extension of the path. If the file name is equal to doNotTranslateFileName,
+// it returns false. If the prefix equals 'values' and file extension
+// equals 'xml', it returns true. False otherwise.
+func isValuesFile(path string) bool {
+	if doNotTranslateFileName == filepath.Base(path) {
+		return false
+	}
+
+	parent := filepath.Base(filepath.Dir(path))
+	return strings.HasPrefix(parent, "values") && strings.EqualFold(".xml", filepath.Ext(path))
+}
+
+// LocaleForValuesFile returns the BCP 47 language tag of the locale qualifier of the
+// values directory containing the given file, e.g. 'pt-BR' for 'values-pt-rBR' and
+// 'sr-Latn' for 'values-b+sr+Latn'. The qualifiers are resolved using the given aliases
+// first. If the directory has no locale qualifier, e.g. 'values' or 'values-night', it
+// returns the DefaultLocale constant.
+func LocaleForValuesFile(path string, aliases map[string]string) string {
+	parent := filepath.Base(filepath.Dir(path))
+	split := strings.SplitN(parent, "-", 2)
+	if len(split) < 2 {
+		return DefaultLocale
+	}
+
+	if locale, ok := parseLocaleQualifier(ResolveLocaleAlias(split[1], aliases)); ok {
+		return locale.languageTag()
+	}
+
+	return DefaultLocale
+}
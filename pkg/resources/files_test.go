@@ -0,0 +1,32 @@
+package resources
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLocaleForValuesFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "app/src/main/res/values/strings.xml", want: DefaultLocale},
+		{path: "app/src/main/res/values-night/strings.xml", want: DefaultLocale},
+		{path: "app/src/main/res/values-sw600dp/strings.xml", want: DefaultLocale},
+		{path: "app/src/main/res/values-de/strings.xml", want: "de"},
+		{path: "app/src/main/res/values-de-night/strings.xml", want: "de"},
+		{path: "app/src/main/res/values-pt-rBR/strings.xml", want: "pt-BR"},
+		{path: "app/src/main/res/values-b+sr+Latn/strings.xml", want: "sr-Latn"},
+		{path: "app/src/main/res/values-mcc310-en-rUS/strings.xml", want: "en-US"},
+		{path: "app/src/main/res/values-iw/strings.xml", want: "he"},
+		{path: "app/src/main/res/values-in-rID/strings.xml", want: "id-ID"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			if got := LocaleForValuesFile(filepath.FromSlash(test.path), DefaultLocaleAliases); got != test.want {
+				t.Errorf("LocaleForValuesFile(%q) = %q, want %q", test.path, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package resources
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// gitIgnoreChecker checks if paths are ignored from being tracked by 'git' using a
+// single long-running 'git check-ignore --stdin' process.
+type gitIgnoreChecker struct {
+	root   string
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	failed bool
+	mutex  sync.Mutex
+}
+
+// newGitIgnoreChecker starts a 'git check-ignore' process in 'root'. If the process
+// fails at any point, e.g. if 'root' isn't in a Git repository, the checker considers
+// all paths as not ignored.
+func newGitIgnoreChecker(root string) *gitIgnoreChecker {
+	checker := &gitIgnoreChecker{root: root, failed: true}
+	cmd := exec.Command("git", "check-ignore", "--stdin", "-z", "--verbose", "--non-matching")
+	cmd.Dir = root
+	cmd.Env = append(os.Environ(), "GIT_FLUSH=1")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return checker
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return checker
+	}
+
+	if err := cmd.Start(); err != nil {
+		return checker
+	}
+
+	checker.cmd, checker.stdin, checker.stdout = cmd, stdin, bufio.NewReader(stdout)
+	checker.failed = false
+	return checker
+}
+
+// isIgnored checks if the given path is ignored from being tracked by 'git'. It returns
+// false, if the checker's root is not an ancestor of the given path.
+func (checker *gitIgnoreChecker) isIgnored(path string) bool {
+	checker.mutex.Lock()
+	defer checker.mutex.Unlock()
+	if checker.failed {
+		return false
+	}
+
+	relPath, err := filepath.Rel(checker.root, path)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return false
+	}
+
+	if _, err := io.WriteString(checker.stdin, relPath+"\x00"); err != nil {
+		checker.failed = true
+		return false
+	}
+
+	// with '--verbose' and '--non-matching', the output contains the source, line
+	// number, pattern and path fields for each path. The source is empty for paths
+	// that don't match any pattern.
+	fields := make([]string, 4)
+	for i := range fields {
+		field, err := checker.stdout.ReadString(0)
+		if err != nil {
+			checker.failed = true
+			return false
+		}
+
+		fields[i] = strings.TrimSuffix(field, "\x00")
+	}
+
+	// negated patterns re-include the paths that they match
+	return fields[0] != "" && !strings.HasPrefix(fields[2], "!")
+}
+
+// close terminates the 'git check-ignore' process.
+func (checker *gitIgnoreChecker) close() {
+	checker.mutex.Lock()
+	defer checker.mutex.Unlock()
+	if checker.cmd == nil {
+		return
+	}
+
+	checker.stdin.Close()
+	checker.cmd.Wait()
+	checker.failed = true
+}
+
+// getLastModifiedTime returns the last modified time of the given line range in the
+// given file using 'git blame'.
+func getLastModifiedTime(file string, lineStart, lineCount int) (time.Time, error) {
+	const errFmt = "unable to find last modified time, file: %q, start: %d, count: %d"
+	const cmdFmt = "git blame -p -L %d,+%d %s | grep committer-time | awk '{ print $2 }'"
+
+	var stdoutBuffer bytes.Buffer
+	command := fmt.Sprintf(cmdFmt, lineStart, lineCount, filepath.Base(file))
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = filepath.Dir(file)
+	cmd.Stdout = &stdoutBuffer
+	if err := cmd.Run(); err != nil {
+		return time.Time{}, errors.Wrapf(err, errFmt, file, lineStart, lineCount)
+	}
+
+	// should handle case where multiline blame returns multiple commits and thus
+	// multiple committer-time fields
+	output := strings.TrimSpace(stdoutBuffer.String())
+	var latestTimestamp int64
+	for _, timestampStr := range strings.Split(output, "\n") {
+		timestamp, err := strconv.ParseInt(timestampStr, 10, 64)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, errFmt, file, lineStart, lineCount)
+		}
+
+		if timestamp > latestTimestamp {
+			latestTimestamp = timestamp
+		}
+	}
+
+	return time.Unix(latestTimestamp, 0), nil
+}
+
+// FindMergeBase returns the best common ancestor of the given git ref and 'HEAD' in the
+// repository containing 'dir'.
+func FindMergeBase(dir, ref string) (string, error) {
+	var stdoutBuffer bytes.Buffer
+	cmd := exec.Command("git", "merge-base", ref, "HEAD")
+	cmd.Dir = dir
+	cmd.Stdout = &stdoutBuffer
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "unable to find merge base of %s and HEAD", ref)
+	}
+
+	return strings.TrimSpace(stdoutBuffer.String()), nil
+}
+
+// getFileAtRevision returns the content of the given file at a git revision. It returns
+// false if the file didn't exist at the revision.
+func getFileAtRevision(file, revision string) ([]byte, bool, error) {
+	object := fmt.Sprintf("%s:./%s", revision, filepath.Base(file))
+	cmd := exec.Command("git", "cat-file", "-e", object)
+	cmd.Dir = filepath.Dir(file)
+	if err := cmd.Run(); err != nil {
+		return nil, false, nil
+	}
+
+	var stdoutBuffer bytes.Buffer
+	cmd = exec.Command("git", "cat-file", "blob", object)
+	cmd.Dir = filepath.Dir(file)
+	cmd.Stdout = &stdoutBuffer
+	if err := cmd.Run(); err != nil {
+		return nil, false, errors.Wrapf(err, "unable to read file at %s in revision %s", file, revision)
+	}
+
+	return stdoutBuffer.Bytes(), true, nil
+}
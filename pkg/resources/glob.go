@@ -0,0 +1,119 @@
+package resources
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// PathFilter declares the glob patterns for filtering the paths relative to the project
+// directory. Patterns use '/' as separator. '*' matches any sequence of characters
+// except '/', '?' matches any single character except '/' and '**' matches any
+// sequence of characters including '/'.
+type PathFilter struct {
+	// Include lists the patterns of the values files to scan. If empty, all values
+	// files are scanned.
+	Include []string
+
+	// Exclude lists the patterns of the paths to skip. If a directory is excluded, all
+	// of its contents are skipped. Exclude takes precedence over Include.
+	Exclude []string
+
+	// Skipped, if not nil, is called with the relative path of each excluded path and
+	// each values file that isn't included.
+	Skipped func(relPath string)
+}
+
+// compiledPathFilter declares the compiled form of a PathFilter.
+type compiledPathFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+	skipped func(relPath string)
+}
+
+// compile converts the patterns in the filter to regular expressions.
+func (filter PathFilter) compile() (*compiledPathFilter, error) {
+	include, err := compileGlobs(filter.Include)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude, err := compileGlobs(filter.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	return &compiledPathFilter{include: include, exclude: exclude, skipped: filter.Skipped}, nil
+}
+
+// isIncluded checks if the given path relative to the project directory matches any of
+// the include patterns. It returns true if there are no include patterns.
+func (filter *compiledPathFilter) isIncluded(relPath string) bool {
+	return len(filter.include) == 0 || matchesAny(filter.include, filepath.ToSlash(relPath))
+}
+
+// isExcluded checks if the given path relative to the project directory matches any of
+// the exclude patterns.
+func (filter *compiledPathFilter) isExcluded(relPath string) bool {
+	return matchesAny(filter.exclude, filepath.ToSlash(relPath))
+}
+
+// skip calls the Skipped callback of the filter, if any, with the given path.
+func (filter *compiledPathFilter) skip(relPath string) {
+	if filter.skipped != nil {
+		filter.skipped(filepath.ToSlash(relPath))
+	}
+}
+
+// compileGlobs converts the given glob patterns to regular expressions.
+func compileGlobs(globs []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(globs))
+	for _, glob := range globs {
+		regex, err := regexp.Compile(globToRegex(glob))
+		if err != nil {
+			return nil, err
+		}
+
+		compiled = append(compiled, regex)
+	}
+
+	return compiled, nil
+}
+
+// globToRegex converts a glob pattern to an anchored regular expression. A leading or
+// trailing '/' in the pattern is ignored.
+func globToRegex(glob string) string {
+	glob = strings.Trim(filepath.ToSlash(glob), "/")
+	var regex strings.Builder
+	regex.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case strings.HasPrefix(glob[i:], "**/"):
+			regex.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			regex.WriteString(".*")
+			i++
+		case c == '*':
+			regex.WriteString("[^/]*")
+		case c == '?':
+			regex.WriteString("[^/]")
+		default:
+			regex.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	regex.WriteString("$")
+	return regex.String()
+}
+
+// matchesAny checks if any of the given regular expressions matches the given string.
+func matchesAny(regexes []*regexp.Regexp, s string) bool {
+	for _, regex := range regexes {
+		if regex.MatchString(s) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,57 @@
+package resources
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestGlobToRegex(t *testing.T) {
+	tests := []struct {
+		glob      string
+		matches   []string
+		unmatches []string
+	}{
+		{
+			glob:      "library/**",
+			matches:   []string{"library/src", "library/src/main/res/values/strings.xml"},
+			unmatches: []string{"app/library/src", "library-ktx/src"},
+		},
+		{
+			glob:      "**/src/debug/**",
+			matches:   []string{"src/debug/res", "app/src/debug/res/values/strings.xml"},
+			unmatches: []string{"app/src/main/res", "app/src/debugger/res"},
+		},
+		{
+			glob:      "app/*/res",
+			matches:   []string{"app/main/res", "app/debug/res"},
+			unmatches: []string{"app/src/main/res", "app/res"},
+		},
+		{
+			glob:      "values-??",
+			matches:   []string{"values-de", "values-fr"},
+			unmatches: []string{"values-pt-rBR", "values-d/e"},
+		},
+		{
+			glob:      "/app/src/main/res.v2/",
+			matches:   []string{"app/src/main/res.v2"},
+			unmatches: []string{"app/src/main/resxv2", "app/src/main/res.v2/values"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.glob, func(t *testing.T) {
+			regex := regexp.MustCompile(globToRegex(test.glob))
+			for _, path := range test.matches {
+				if !regex.MatchString(path) {
+					t.Errorf("globToRegex(%q) = %q doesn't match %q", test.glob, regex, path)
+				}
+			}
+
+			for _, path := range test.unmatches {
+				if regex.MatchString(path) {
+					t.Errorf("globToRegex(%q) = %q matches %q", test.glob, regex, path)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package resources
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// gradleLocaleFiltersRegex matches the properties of the Android Gradle Plugin that
+	// restrict the locales packaged in the app, e.g. 'resConfigs "en", "de"' and
+	// 'androidResources.localeFilters += listOf("en", "de")'.
+	gradleLocaleFiltersRegex = regexp.MustCompile(`\b(resConfigs?|resourceConfigurations|localeFilters)\b`)
+
+	// gradleStringRegex matches the single or double quoted string literals.
+	gradleStringRegex = regexp.MustCompile(`"([^"\s]*)"|'([^'\s]*)'`)
+)
+
+// FindGradleLocaleFilters finds the Gradle build scripts, i.e. 'build.gradle' and
+// 'build.gradle.kts' files, in 'dir' and returns the locales declared using
+// 'resConfigs', 'resourceConfigurations' or 'localeFilters' in any of them. Other
+// resource qualifiers, e.g. 'xxhdpi', are skipped. It returns an empty slice if none of
+// the build scripts restricts the locales.
+func FindGradleLocaleFilters(dir string) ([]string, error) {
+	scripts, err := findFiles(dir, func(string) bool { return true }, isGradleScript)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool)
+	for _, script := range scripts {
+		content, err := ioutil.ReadFile(script)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read file at %s", script)
+		}
+
+		for _, match := range gradleLocaleFiltersRegex.FindAllIndex(content, -1) {
+			args := findGradleArguments(string(content), match[1])
+			for _, literal := range gradleStringRegex.FindAllStringSubmatch(args, -1) {
+				value := literal[1] + literal[2]
+				if _, ok := parseLocaleQualifier(value); ok {
+					found[value] = true
+				}
+			}
+		}
+	}
+
+	filters := make([]string, 0, len(found))
+	for filter := range found {
+		filters = append(filters, filter)
+	}
+
+	sort.Strings(filters)
+	return filters, nil
+}
+
+// isGradleScript checks if the given path is a Groovy or Kotlin Gradle build script.
+func isGradleScript(path string) bool {
+	name := filepath.Base(path)
+	return name == "build.gradle" || name == "build.gradle.kts"
+}
+
+// findGradleArguments returns the content from 'start' to the end of the line. If the
+// line opens any parentheses or brackets, e.g. for multi-line lists, the content is
+// extended until they are closed.
+func findGradleArguments(content string, start int) string {
+	depth := 0
+	for i := start; i < len(content); i++ {
+		switch content[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+			if depth <= 0 {
+				return content[start : i+1]
+			}
+		case '\n':
+			if depth == 0 {
+				return content[start:i]
+			}
+		}
+	}
+
+	return content[start:]
+}
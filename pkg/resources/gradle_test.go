@@ -0,0 +1,62 @@
+package resources
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindGradleLocaleFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		scripts map[string]string
+		want    []string
+	}{
+		{
+			name:    "no filters",
+			scripts: map[string]string{"app/build.gradle": "android {\n    compileSdkVersion 30\n}\n"},
+			want:    []string{},
+		},
+		{
+			name: "groovy resConfigs",
+			scripts: map[string]string{
+				"app/build.gradle": "android {\n    defaultConfig {\n        resConfigs \"en\", 'de', \"xxhdpi\"\n    }\n}\n",
+			},
+			want: []string{"de", "en"},
+		},
+		{
+			name: "kotlin resourceConfigurations",
+			scripts: map[string]string{
+				"app/build.gradle.kts": "android {\n    defaultConfig {\n        resourceConfigurations += listOf(\n            \"en\",\n            \"pt-rBR\",\n        )\n    }\n}\n",
+			},
+			want: []string{"en", "pt-rBR"},
+		},
+		{
+			name: "localeFilters in several modules",
+			scripts: map[string]string{
+				"app/build.gradle.kts": "android {\n    androidResources.localeFilters += setOf(\"en\", \"b+sr+Latn\")\n}\n",
+				"lib/build.gradle":     "android {\n    defaultConfig { resConfig \"fr\" }\n}\n",
+				"lib/other.gradle":     "resConfigs \"es\"\n",
+			},
+			want: []string{"b+sr+Latn", "en", "fr"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for path, content := range test.scripts {
+				writeTestFile(t, filepath.Join(dir, filepath.FromSlash(path)), content)
+			}
+
+			got, err := FindGradleLocaleFilters(dir)
+			if err != nil {
+				t.Fatalf("FindGradleLocaleFilters() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("FindGradleLocaleFilters() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package resources
+
+import (
+	"strings"
+	"unicode"
+)
+
+// IsIdenticalTranslation checks if the translated resource is a verbatim copy of the
+// default resource. Values without any letters outside of their format specifiers and
+// named placeholders, e.g. '%1$d / %2$d', are never considered identical since they
+// don't need translating. For 'plurals', the quantities missing in the default resource
+// are compared with its 'other' quantity.
+func IsIdenticalTranslation(defaultRes, translatedRes Resource) bool {
+	if defaultRes.Type != translatedRes.Type {
+		return false
+	}
+
+	switch defaultRes.Type {
+	case TypeStringArray:
+		if len(defaultRes.Items) == 0 || len(defaultRes.Items) != len(translatedRes.Items) {
+			return false
+		}
+
+		for i, item := range defaultRes.Items {
+			if item != translatedRes.Items[i] || !hasTranslatableText(item) {
+				return false
+			}
+		}
+
+		return true
+	case TypePlurals:
+		if len(translatedRes.Quantities) == 0 {
+			return false
+		}
+
+		for quantity, value := range translatedRes.Quantities {
+			defaultValue, ok := defaultRes.Quantities[quantity]
+			if !ok {
+				defaultValue = defaultRes.Quantities["other"]
+			}
+
+			if value != defaultValue || !hasTranslatableText(value) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return defaultRes.Value == translatedRes.Value && hasTranslatableText(defaultRes.Value)
+	}
+}
+
+// hasTranslatableText checks if the value contains any letters outside of its format
+// specifiers, named placeholders and '\n' escapes.
+func hasTranslatableText(value string) bool {
+	value = formatSpecifierRegex.ReplaceAllString(value, "")
+	value = placeholderRegex.ReplaceAllString(value, "")
+	value = strings.ReplaceAll(value, newlineEscape, "")
+	return strings.IndexFunc(value, unicode.IsLetter) >= 0
+}
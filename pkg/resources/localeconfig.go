@@ -0,0 +1,154 @@
+package resources
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// xmlManifest declares data structure for unmarshalling the attributes of 'application'
+// tag in 'AndroidManifest.xml' files.
+type xmlManifest struct {
+	xml.Name    `xml:"manifest"`
+	Application struct {
+		LocaleConfig string `xml:"localeConfig,attr"`
+	} `xml:"application"`
+}
+
+// xmlLocaleConfig declares data structure for unmarshalling 'locale-config' tag in
+// XML resources referenced by the 'android:localeConfig' attribute in manifests.
+type xmlLocaleConfig struct {
+	xml.Name `xml:"locale-config"`
+	Locales  []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"locale"`
+}
+
+// ValidateLocaleConfigs finds 'AndroidManifest.xml' files in 'dir' and validates the
+// locales declared in the 'android:localeConfig' resources that they reference. It
+// returns a configuration error for each declared locale that isn't served by any
+// translation and for each translated locale that isn't declared. The language of
+// the default string resources, 'defaultLanguage', is always considered to be
+// translated. The declared locales are resolved using the given aliases, e.g. 'iw'
+// is the same as 'he'.
+func ValidateLocaleConfigs(dir string, defaultLanguage string, localeStrings LocaleResources, aliases map[string]string) ([]string, error) {
+	manifests, err := findFiles(dir, func(string) bool { return true }, isManifestFile)
+	if err != nil {
+		return nil, err
+	}
+
+	translated := make([]string, 0)
+	for locale := range localeStrings {
+		if locale != DefaultLocale {
+			translated = append(translated, LocaleToLanguageTag(locale))
+		}
+	}
+
+	sort.Strings(translated)
+	configErrors := make([]string, 0)
+	for _, manifest := range manifests {
+		configFile, declared, err := findDeclaredLocales(manifest, aliases)
+		if err != nil {
+			configErrors = append(configErrors, err.Error())
+			continue
+		}
+
+		if configFile == "" { // localeConfig isn't in use
+			continue
+		}
+
+		for _, declaredTag := range declared {
+			if strings.EqualFold(strings.SplitN(declaredTag, "-", 2)[0], defaultLanguage) {
+				continue
+			}
+
+			served := false
+			for _, translatedTag := range translated {
+				served = served || isLanguageTagServedBy(declaredTag, translatedTag)
+			}
+
+			if !served {
+				const errFmt = "locale %q is declared in %s but it has no translations"
+				configErrors = append(configErrors, fmt.Sprintf(errFmt, declaredTag, configFile))
+			}
+		}
+
+		for _, translatedTag := range translated {
+			isDeclared := false
+			for _, declaredTag := range declared {
+				isDeclared = isDeclared || isLanguageTagServedBy(declaredTag, translatedTag)
+			}
+
+			if !isDeclared {
+				const errFmt = "locale %q has translations but it is not declared in %s"
+				configErrors = append(configErrors, fmt.Sprintf(errFmt, translatedTag, configFile))
+			}
+		}
+	}
+
+	return configErrors, nil
+}
+
+// isManifestFile checks if the base name of the given path is 'AndroidManifest.xml'.
+func isManifestFile(path string) bool {
+	return filepath.Base(path) == "AndroidManifest.xml"
+}
+
+// findDeclaredLocales parses the given manifest file and resolves its
+// 'android:localeConfig' attribute to an XML resource in the sibling 'res/xml'
+// directory. It returns the path of the resolved resource and the locales declared in
+// it, normalized using the given aliases. If the manifest doesn't use the attribute, it
+// returns an empty path.
+func findDeclaredLocales(manifestFile string, aliases map[string]string) (string, []string, error) {
+	content, err := ioutil.ReadFile(manifestFile)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "unable to read file at %s", manifestFile)
+	}
+
+	manifest := &xmlManifest{}
+	if err = xml.Unmarshal(content, manifest); err != nil {
+		return "", nil, errors.Wrapf(err, "unable to parse XML file at %s", manifestFile)
+	}
+
+	ref := manifest.Application.LocaleConfig
+	if ref == "" {
+		return "", nil, nil
+	}
+
+	if !strings.HasPrefix(ref, "@xml/") {
+		const errFmt = "invalid localeConfig %q in %s, must reference an XML resource"
+		return "", nil, fmt.Errorf(errFmt, ref, manifestFile)
+	}
+
+	configFile := filepath.Join(filepath.Dir(manifestFile), "res", "xml", strings.TrimPrefix(ref, "@xml/")+".xml")
+	content, err = ioutil.ReadFile(configFile)
+	if err != nil {
+		const errFmt = "unable to read localeConfig %q referenced in %s"
+		return "", nil, errors.Wrapf(err, errFmt, ref, manifestFile)
+	}
+
+	localeConfig := &xmlLocaleConfig{}
+	if err = xml.Unmarshal(content, localeConfig); err != nil {
+		return "", nil, errors.Wrapf(err, "unable to parse XML file at %s", configFile)
+	}
+
+	declared := make([]string, 0, len(localeConfig.Locales))
+	for _, locale := range localeConfig.Locales {
+		declared = append(declared, NormalizeLocale(strings.TrimSpace(locale.Name), aliases))
+	}
+
+	return configFile, declared, nil
+}
+
+// isLanguageTagServedBy checks if the resources translated for 'translated' language tag
+// are served to the users of 'declared' language tag, i.e. both tags are equal or the
+// 'declared' tag is a more specific variant of the 'translated' tag.
+func isLanguageTagServedBy(declared, translated string) bool {
+	declared, translated = strings.ToLower(declared), strings.ToLower(translated)
+	return declared == translated || strings.HasPrefix(declared, translated+"-")
+}
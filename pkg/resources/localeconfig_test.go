@@ -0,0 +1,82 @@
+package resources
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateLocaleConfigs(t *testing.T) {
+	tests := []struct {
+		name     string
+		declared string
+		locales  []string
+		want     []string
+	}{
+		{
+			name:     "valid",
+			declared: `<locale android:name="en"/><locale android:name="de-DE"/>`,
+			locales:  []string{"de"},
+			want:     []string{},
+		},
+		{
+			name:     "aliases",
+			declared: `<locale android:name="en"/><locale android:name="iw"/><locale android:name="in-ID"/>`,
+			locales:  []string{"he", "id"},
+			want:     []string{},
+		},
+		{
+			name:     "undeclared and untranslated",
+			declared: `<locale android:name="en"/><locale android:name="fr"/>`,
+			locales:  []string{"de"},
+			want: []string{
+				`locale "fr" is declared in app/res/xml/locales_config.xml but it has no translations`,
+				`locale "de" has translations but it is not declared in app/res/xml/locales_config.xml`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestFile(t, filepath.Join(dir, "app", "AndroidManifest.xml"), `<manifest
+  xmlns:android="http://schemas.android.com/apk/res/android">
+  <application android:localeConfig="@xml/locales_config"/>
+</manifest>`)
+			writeTestFile(t, filepath.Join(dir, "app", "res", "xml", "locales_config.xml"), `<locale-config
+  xmlns:android="http://schemas.android.com/apk/res/android">`+test.declared+`</locale-config>`)
+
+			localeStrings := LocaleResources{DefaultLocale: {}}
+			for _, locale := range test.locales {
+				localeStrings[locale] = map[string]Resource{}
+			}
+
+			got, err := ValidateLocaleConfigs(dir, "en", localeStrings, DefaultLocaleAliases)
+			if err != nil {
+				t.Fatalf("ValidateLocaleConfigs() error = %v", err)
+			}
+
+			for i := range got {
+				got[i] = filepath.ToSlash(strings.Replace(got[i], dir+string(filepath.Separator), "", 1))
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ValidateLocaleConfigs() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// writeTestFile writes the given content to the file, creating its parent directory.
+func writeTestFile(t *testing.T, file, content string) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,379 @@
+package resources
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// DefaultLocaleAliases maps the deprecated ISO 639 language codes, that Android still
+// requires for resource directories, to their current equivalents.
+var DefaultLocaleAliases = map[string]string{
+	"iw": "he", // Hebrew
+	"in": "id", // Indonesian
+	"ji": "yi", // Yiddish
+}
+
+// supportedLanguages declares the language codes that Android and Google Play can serve
+// resources for. It contains all ISO 639-1 codes, the deprecated ISO 639-1 codes that
+// Android still requires for resource directories and the ISO 639-2/3 codes of the
+// languages with locale data in CLDR.
+var supportedLanguages = toSet(`
+	aa ab ae af ak am an ar as av ay az ba be bg bh bi bm bn bo br bs ca ce ch co cr cs
+	cu cv cy da de dv dz ee el en eo es et eu fa ff fi fj fo fr fy ga gd gl gn gu gv ha
+	he hi ho hr ht hu hy hz ia id ie ig ii ik io is it iu ja jv ka kg ki kj kk kl km kn
+	ko kr ks ku kv kw ky la lb lg li ln lo lt lu lv mg mh mi mk ml mn mr ms mt my na nb
+	nd ne ng nl nn no nr nv ny oc oj om or os pa pi pl ps pt qu rm rn ro ru rw sa sc sd
+	se sg si sk sl sm sn so sq sr ss st su sv sw ta te tg th ti tk tl tn to tr ts tt tw
+	ty ug uk ur uz ve vi vo wa wo xh yi yo za zh zu
+	iw in ji
+	agq ars ast asa bas bem bez bgc bho brx ccp ceb cgg chr ckb dav dje doi dsb dua dyo
+	ebu ewo fil fur gsw guz haw hsb jgo jmc kab kam kde kea kgp khq kkj kln kok ksb ksf
+	ksh lag lkt lrc luo luy mai mas mer mfe mgh mgo mni mua mzn naq nds nmg nnh nus nyn
+	pcm raj rof rwk sah saq sat sbp seh ses shi smn teo twq tzm vai vun wae xog yav yrl
+	yue zgh
+`)
+
+// supportedRegions declares the ISO 3166-1 alpha-2 region codes.
+var supportedRegions = toSet(`
+	AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN
+	BO BQ BR BS BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ
+	DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO FR GA GB GD GE GF GG GH GI GL
+	GM GN GP GQ GR GS GT GU GW GY HK HM HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM
+	JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME
+	MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP
+	NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW SA SB SC SD
+	SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO
+	TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW
+	XK
+`)
+
+// pluralQuantities maps languages to the quantities that their 'plurals' resources must
+// define, i.e. the cardinal plural categories defined by CLDR for those languages.
+var pluralQuantities = toPluralQuantitiesMap(map[string]string{
+	"other": `bm bo dz id ig ii in ja jbo jv jw kde kea km ko lkt lo ms my nqo osa sah ses sg
+		su th to tpi vi wo yo yue zh`,
+	"one other": `af ak am an as asa ast az bal bem bez bg bho bn brx ce ceb cgg chr ckb da de
+		doi dv ee el en eo et eu fa ff fi fil fo fur fy gl gsw gu guw ha haw hi hu hy ia io
+		is ji ka kab kaj kcg kk kkj kl kn ks ksb ku ky lb lg lij ln mas mg mgo mk ml mn mr
+		nah nb nd ne nl nn nnh no nr nso ny nyn om or os pa pap pcm ps rm rof rwk saq sc sd
+		sdh seh si sn so sq ss ssy st sv sw syr ta te teo ti tig tk tl tn tr ts ug ur uz ve
+		vo vun wa wae xh xog yi zu`,
+	"zero one other":              "ksh lag lv prg",
+	"one two other":               "he iw iu naq sat se sma smi smj smn sms",
+	"one few other":               "bs hr mo ro sh shi sr",
+	"one many other":              "ca es fr it pt",
+	"one few many other":          "be cs lt pl ru sk uk",
+	"one two few other":           "dsb gd hsb sl",
+	"one two few many other":      "br ga gv mt",
+	"zero one two few many other": "ar ars cy kw",
+})
+
+// nonLocaleQualifiers declares the resource directory qualifiers that can be mistaken
+// for a language code.
+var nonLocaleQualifiers = toSet("car")
+
+var (
+	languageRegex = regexp.MustCompile(`^[a-zA-Z]{2,3}$`)
+	scriptRegex   = regexp.MustCompile(`^[a-zA-Z]{4}$`)
+	regionRegex   = regexp.MustCompile(`^([a-zA-Z]{2}|[0-9]{3})$`)
+	variantRegex  = regexp.MustCompile(`^([a-zA-Z0-9]{5,8}|[0-9][a-zA-Z0-9]{3})$`)
+	mccMncRegex   = regexp.MustCompile(`^(mcc|mnc)[0-9]+$`)
+
+	// tagScriptRegex and tagRegionRegex match the script and region subtags of BCP 47
+	// language tags in their canonical case. Unlike the other qualifiers, these are
+	// never lowercase, so they can't be mistaken for the qualifiers that follow the
+	// locale, e.g. 'land' or 'night'.
+	tagScriptRegex = regexp.MustCompile(`^[A-Z][a-z]{3}$`)
+	tagRegionRegex = regexp.MustCompile(`^([A-Z]{2}|[0-9]{3})$`)
+)
+
+// localeQualifier declares the locale specific parts of resource directory qualifiers.
+type localeQualifier struct {
+	Language string
+	Script   string
+	Region   string
+	Variants []string
+}
+
+// languageTag returns the BCP 47 language tag of the locale, e.g. 'sr-Latn-RS'.
+func (locale localeQualifier) languageTag() string {
+	subtags := []string{locale.Language}
+	for _, subtag := range append([]string{locale.Script, locale.Region}, locale.Variants...) {
+		if subtag != "" {
+			subtags = append(subtags, subtag)
+		}
+	}
+
+	return strings.Join(subtags, "-")
+}
+
+// parseLocaleQualifier finds the locale qualifier in the given '-' separated resource
+// directory qualifiers, e.g. 'de-rAT-night' or 'b+sr+Latn'. As per the qualifier order
+// defined by Android, the locale qualifier can only be preceded by the MCC and MNC
+// qualifiers. BCP 47 language tags in their canonical case, e.g. 'pt-BR' or 'sr-Latn',
+// are also accepted. It returns false if the qualifiers don't contain a locale, e.g.
+// 'night' or 'sw600dp'.
+func parseLocaleQualifier(qualifiers string) (localeQualifier, bool) {
+	split := strings.Split(qualifiers, "-")
+	for len(split) > 0 && mccMncRegex.MatchString(split[0]) {
+		split = split[1:]
+	}
+
+	if len(split) == 0 {
+		return localeQualifier{}, false
+	}
+
+	if strings.HasPrefix(split[0], "b+") {
+		subtags := strings.Split(strings.TrimPrefix(split[0], "b+"), "+")
+		if !languageRegex.MatchString(subtags[0]) {
+			return localeQualifier{}, false
+		}
+
+		locale := localeQualifier{Language: strings.ToLower(subtags[0])}
+		for _, subtag := range subtags[1:] {
+			if locale.Script == "" && locale.Region == "" && len(locale.Variants) == 0 && scriptRegex.MatchString(subtag) {
+				locale.Script = strings.ToUpper(subtag[:1]) + strings.ToLower(subtag[1:])
+			} else if locale.Region == "" && len(locale.Variants) == 0 && regionRegex.MatchString(subtag) {
+				locale.Region = strings.ToUpper(subtag)
+			} else if variantRegex.MatchString(subtag) {
+				locale.Variants = append(locale.Variants, strings.ToLower(subtag))
+			}
+		}
+
+		return locale, true
+	}
+
+	if !languageRegex.MatchString(split[0]) || nonLocaleQualifiers[strings.ToLower(split[0])] {
+		return localeQualifier{}, false
+	}
+
+	locale := localeQualifier{Language: strings.ToLower(split[0])}
+	split = split[1:]
+	if len(split) > 0 && tagScriptRegex.MatchString(split[0]) {
+		locale.Script = split[0]
+		split = split[1:]
+	}
+
+	if len(split) > 0 && len(split[0]) == 3 && split[0][0] == 'r' && regionRegex.MatchString(split[0][1:]) {
+		locale.Region = strings.ToUpper(split[0][1:])
+	} else if len(split) > 0 && tagRegionRegex.MatchString(split[0]) {
+		locale.Region = split[0]
+	}
+
+	return locale, true
+}
+
+// ParseLocaleAliases returns a new alias map containing 'defaults' overridden by the
+// given 'suffix=locale' pairs. Suffixes are accepted with or without the 'values-'
+// prefix so that 'values-iw=he' and 'iw=he' are equivalent.
+func ParseLocaleAliases(defaults map[string]string, pairs []string) (map[string]string, error) {
+	aliases := make(map[string]string, len(defaults)+len(pairs))
+	for suffix, locale := range defaults {
+		aliases[suffix] = locale
+	}
+
+	for _, pair := range pairs {
+		split := strings.SplitN(pair, "=", 2)
+		if len(split) < 2 || strings.TrimSpace(split[0]) == "" || strings.TrimSpace(split[1]) == "" {
+			return nil, fmt.Errorf("invalid locale alias %q, must be formatted as suffix=locale", pair)
+		}
+
+		suffix := strings.TrimPrefix(strings.TrimSpace(split[0]), "values-")
+		aliases[suffix] = strings.TrimSpace(split[1])
+	}
+
+	return aliases, nil
+}
+
+// ResolveLocaleAlias returns the canonical locale for the given 'values-' suffix. An
+// alias for the complete suffix takes precedence over an alias for its language part,
+// e.g. with 'iw=he', 'iw-rIL' resolves to 'he-rIL'. If no alias matches, it returns
+// the suffix as is.
+func ResolveLocaleAlias(suffix string, aliases map[string]string) string {
+	if locale, ok := aliases[suffix]; ok {
+		return locale
+	}
+
+	split := strings.SplitN(suffix, "-", 2)
+	if locale, ok := aliases[split[0]]; ok {
+		split[0] = locale
+		return strings.Join(split, "-")
+	}
+
+	return suffix
+}
+
+// LocaleToLanguageTag converts a 'values-' suffix to the BCP 47 language tag of its
+// locale, e.g. 'pt-rBR' becomes 'pt-BR' and 'b+sr+Latn' becomes 'sr-Latn'. The
+// qualifiers following the locale are dropped, e.g. 'de-night' becomes 'de'. Language
+// tags are returned as is. If the suffix doesn't contain a locale, it is returned as
+// is.
+func LocaleToLanguageTag(locale string) string {
+	if qualifier, ok := parseLocaleQualifier(locale); ok {
+		return qualifier.languageTag()
+	}
+
+	return locale
+}
+
+// scriptDisplayNames declares the names of the scripts in locale names that differ from
+// their standalone names, e.g. 'Chinese (Simplified)' instead of 'Chinese (Simplified
+// Han)'.
+var scriptDisplayNames = map[string]string{
+	"Hans": "Simplified",
+	"Hant": "Traditional",
+}
+
+// LocaleDisplayName returns the English name of the given locale, e.g. 'Chinese
+// (China)' for 'zh-CN' or 'Serbian (Latin)' for 'sr-Latn'. The name is composed of the
+// names of the locale's language, script and region, since the names of some complete
+// tags are outdated, e.g. 'Serbo-Croatian' for 'sr-Latn'. It returns an empty string
+// if the name of the language isn't known.
+func LocaleDisplayName(locale string) string {
+	tag, err := language.Parse(LocaleToLanguageTag(locale))
+	if err != nil {
+		return ""
+	}
+
+	base, _ := tag.Base()
+	name := display.English.Languages().Name(base)
+	if name == "" {
+		return ""
+	}
+
+	details := make([]string, 0, 2)
+	if script, confidence := tag.Script(); confidence == language.Exact {
+		if scriptName, ok := scriptDisplayNames[script.String()]; ok {
+			details = append(details, scriptName)
+		} else {
+			details = append(details, display.English.Scripts().Name(script))
+		}
+	}
+
+	if region, confidence := tag.Region(); confidence == language.Exact {
+		details = append(details, display.English.Regions().Name(region))
+	}
+
+	if len(details) > 0 {
+		name = fmt.Sprintf("%s (%s)", name, strings.Join(details, ", "))
+	}
+
+	return name
+}
+
+// NormalizeLocale returns the BCP 47 language tag of the given locale, which can be a
+// 'values-' suffix, with or without the 'values-' prefix, or a language tag, e.g.
+// 'pt-rBR' or 'pt-BR', after resolving it using the given aliases. The returned tag
+// matches the locales of the values files, see LocaleForValuesFile.
+func NormalizeLocale(locale string, aliases map[string]string) string {
+	return LocaleToLanguageTag(ResolveLocaleAlias(strings.TrimPrefix(locale, "values-"), aliases))
+}
+
+// IsLocaleIncluded checks if the given locale is included by any of the given filters.
+// Filters can be 'values-' suffixes or BCP 47 language tags, e.g. 'pt-rBR' or 'pt-BR',
+// and are resolved using the given aliases. A filter includes its locale and the more
+// specific locales, e.g. 'pt' includes 'pt-rBR'.
+func IsLocaleIncluded(locale string, filters []string, aliases map[string]string) bool {
+	tag := LocaleToLanguageTag(locale)
+	for _, filter := range filters {
+		if isLanguageTagServedBy(tag, NormalizeLocale(filter, aliases)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FindUnsupportedLocales returns an error for each distinct 'values-' suffix in the
+// given files whose locale is never served to the users.
+func FindUnsupportedLocales(valuesFiles []string) []error {
+	checked := make(map[string]bool)
+	unsupported := make([]error, 0)
+	for _, file := range valuesFiles {
+		parent := filepath.Base(filepath.Dir(file))
+		if checked[parent] || !strings.HasPrefix(parent, "values-") {
+			continue
+		}
+
+		checked[parent] = true
+		if err := validateLocaleSupport(strings.TrimPrefix(parent, "values-")); err != nil {
+			unsupported = append(unsupported, err)
+		}
+	}
+
+	return unsupported
+}
+
+// validateLocaleSupport checks if the locale in the given resource directory qualifiers
+// can be served to the users. It returns a non-nil error describing the problem if the
+// language or the region of the locale is not recognised.
+func validateLocaleSupport(qualifiers string) error {
+	locale, ok := parseLocaleQualifier(qualifiers)
+	if !ok {
+		return nil
+	}
+
+	const errFmt = "%s %q in 'values-%s' is not supported, its resources will never be served"
+	if !supportedLanguages[locale.Language] {
+		return fmt.Errorf(errFmt, "language", locale.Language, qualifiers)
+	}
+
+	if locale.Region != "" && !regionRegex.MatchString(locale.Region) {
+		return fmt.Errorf(errFmt, "region", locale.Region, qualifiers)
+	}
+
+	if len(locale.Region) == 2 && !supportedRegions[locale.Region] {
+		return fmt.Errorf(errFmt, "region", locale.Region, qualifiers)
+	}
+
+	return nil
+}
+
+// FindMissingQuantities returns the quantities that the 'plurals' resource with given
+// quantities must define for the given locale but doesn't. If the plural rules of the
+// locale's language aren't known, only the 'other' quantity is required.
+func FindMissingQuantities(locale string, quantities map[string]string) []string {
+	required := []string{"other"}
+	if qualifier, ok := parseLocaleQualifier(locale); ok {
+		if languageQuantities, ok := pluralQuantities[qualifier.Language]; ok {
+			required = languageQuantities
+		}
+	}
+
+	missing := make([]string, 0)
+	for _, quantity := range required {
+		if _, ok := quantities[quantity]; !ok {
+			missing = append(missing, quantity)
+		}
+	}
+
+	return missing
+}
+
+// toPluralQuantitiesMap inverts the given mapping of space separated quantities to
+// whitespace separated languages.
+func toPluralQuantitiesMap(quantitiesLanguages map[string]string) map[string][]string {
+	languageQuantities := make(map[string][]string)
+	for quantities, languages := range quantitiesLanguages {
+		for language := range toSet(languages) {
+			languageQuantities[language] = strings.Fields(quantities)
+		}
+	}
+
+	return languageQuantities
+}
+
+// toSet splits the given string around whitespace and returns a set of its fields.
+func toSet(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, field := range strings.Fields(s) {
+		set[field] = true
+	}
+
+	return set
+}
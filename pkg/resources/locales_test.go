@@ -0,0 +1,145 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLocaleQualifier(t *testing.T) {
+	tests := []struct {
+		qualifiers string
+		want       string
+		wantOK     bool
+	}{
+		{qualifiers: "de", want: "de", wantOK: true},
+		{qualifiers: "pt-rBR", want: "pt-BR", wantOK: true},
+		{qualifiers: "DE-rat", want: "de-AT", wantOK: true},
+		{qualifiers: "de-rAT-night", want: "de-AT", wantOK: true},
+		{qualifiers: "mcc310-mnc004-en-rUS", want: "en-US", wantOK: true},
+		{qualifiers: "b+sr+Latn", want: "sr-Latn", wantOK: true},
+		{qualifiers: "b+sr+latn+rs", want: "sr-Latn-RS", wantOK: true},
+		{qualifiers: "b+es+419", want: "es-419", wantOK: true},
+		{qualifiers: "pt-BR", want: "pt-BR", wantOK: true},
+		{qualifiers: "sr-Latn", want: "sr-Latn", wantOK: true},
+		{qualifiers: "night", wantOK: false},
+		{qualifiers: "sw600dp", wantOK: false},
+		{qualifiers: "car", wantOK: false},
+		{qualifiers: "mcc310", wantOK: false},
+		{qualifiers: "b+1234", wantOK: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.qualifiers, func(t *testing.T) {
+			locale, ok := parseLocaleQualifier(test.qualifiers)
+			if ok != test.wantOK {
+				t.Fatalf("parseLocaleQualifier(%q) ok = %v, want %v", test.qualifiers, ok, test.wantOK)
+			}
+
+			if got := locale.languageTag(); ok && got != test.want {
+				t.Errorf("parseLocaleQualifier(%q) = %q, want %q", test.qualifiers, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{locale: "de", want: "de"},
+		{locale: "values-de", want: "de"},
+		{locale: "pt-rBR", want: "pt-BR"},
+		{locale: "values-pt-rBR", want: "pt-BR"},
+		{locale: "pt-BR", want: "pt-BR"},
+		{locale: "iw", want: "he"},
+		{locale: "in-rID", want: "id-ID"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.locale, func(t *testing.T) {
+			if got := NormalizeLocale(test.locale, DefaultLocaleAliases); got != test.want {
+				t.Errorf("NormalizeLocale(%q) = %q, want %q", test.locale, got, test.want)
+			}
+		})
+	}
+}
+
+func TestLocaleDisplayName(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{locale: "de", want: "German"},
+		{locale: "zh-rCN", want: "Chinese (China)"},
+		{locale: "pt-BR", want: "Portuguese (Brazil)"},
+		{locale: "sr-Latn", want: "Serbian (Latin)"},
+		{locale: "b+sr+Latn", want: "Serbian (Latin)"},
+		{locale: "b+zh+Hans", want: "Chinese (Simplified)"},
+		{locale: "b+zh+Hant+TW", want: "Chinese (Traditional, Taiwan)"},
+		{locale: "he", want: "Hebrew"},
+		{locale: "default", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.locale, func(t *testing.T) {
+			if got := LocaleDisplayName(test.locale); got != test.want {
+				t.Errorf("LocaleDisplayName(%q) = %q, want %q", test.locale, got, test.want)
+			}
+		})
+	}
+}
+
+func TestFindMissingQuantities(t *testing.T) {
+	tests := []struct {
+		name       string
+		locale     string
+		quantities map[string]string
+		want       []string
+	}{
+		{
+			name:       "complete",
+			locale:     "en",
+			quantities: map[string]string{"one": "%d item", "other": "%d items"},
+			want:       []string{},
+		},
+		{
+			name:       "missing one",
+			locale:     "de",
+			quantities: map[string]string{"other": "%d Elemente"},
+			want:       []string{"one"},
+		},
+		{
+			name:       "region qualifier",
+			locale:     "ru-RU",
+			quantities: map[string]string{"one": "%d", "other": "%d"},
+			want:       []string{"few", "many"},
+		},
+		{
+			name:       "extra quantities",
+			locale:     "ja",
+			quantities: map[string]string{"one": "%d", "other": "%d"},
+			want:       []string{},
+		},
+		{
+			name:       "unknown language",
+			locale:     "xx",
+			quantities: map[string]string{"one": "%d"},
+			want:       []string{"other"},
+		},
+		{
+			name:       "empty",
+			locale:     "ar",
+			quantities: map[string]string{},
+			want:       []string{"zero", "one", "two", "few", "many", "other"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := FindMissingQuantities(test.locale, test.quantities); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("FindMissingQuantities(%q) = %v, want %v", test.locale, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package resources
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// formatSpecifierRegex matches the format specifiers recognised by 'java.util.Formatter',
+	// i.e. '%[argument_index$][flags][width][.precision]conversion'. The ' ' flag is left
+	// out so that a literal '%' followed by a word, e.g. '50% on', isn't a specifier.
+	formatSpecifierRegex = regexp.MustCompile(
+		`%(?:(\d+)\$)?[-#+0,(<]*\d*(?:\.\d+)?([tT][a-zA-Z]|[bBhHsScCdoxXeEfgGaA%n])`)
+
+	// placeholderRegex matches the named '{placeholder}' tokens.
+	placeholderRegex = regexp.MustCompile(`\{[a-zA-Z_][a-zA-Z0-9_]*\}`)
+)
+
+// newlineEscape is the escape sequence for line breaks in values XML files.
+const newlineEscape = `\n`
+
+// FindPlaceholderMismatches compares the format specifiers, '\n' escapes and named
+// '{placeholder}' tokens of the default resource with its translation. It returns
+// a description of each mismatch. For 'plurals', only the 'other' quantities are
+// compared since the other quantities may legitimately omit the count. For
+// 'string-array', the items are compared pairwise if both have the same item count.
+func FindPlaceholderMismatches(defaultRes, translatedRes Resource) []string {
+	mismatches := make([]string, 0)
+	switch defaultRes.Type {
+	case TypeString:
+		if defaultRes.Formatted && translatedRes.Formatted {
+			mismatches = append(mismatches, compareFormatSpecifiers(defaultRes.Value, translatedRes.Value)...)
+		}
+
+		mismatches = append(mismatches, comparePlaceholders(defaultRes.Value, translatedRes.Value)...)
+	case TypePlurals:
+		defaultValue, ok1 := defaultRes.Quantities["other"]
+		translatedValue, ok2 := translatedRes.Quantities["other"]
+		if ok1 && ok2 {
+			mismatches = append(mismatches, compareFormatSpecifiers(defaultValue, translatedValue)...)
+			mismatches = append(mismatches, comparePlaceholders(defaultValue, translatedValue)...)
+		}
+	case TypeStringArray:
+		if len(defaultRes.Items) != len(translatedRes.Items) {
+			break
+		}
+
+		for i := range defaultRes.Items {
+			for _, mismatch := range compareFormatSpecifiers(defaultRes.Items[i], translatedRes.Items[i]) {
+				mismatches = append(mismatches, fmt.Sprintf("item %d: %s", i, mismatch))
+			}
+
+			for _, mismatch := range comparePlaceholders(defaultRes.Items[i], translatedRes.Items[i]) {
+				mismatches = append(mismatches, fmt.Sprintf("item %d: %s", i, mismatch))
+			}
+		}
+	}
+
+	return mismatches
+}
+
+// compareFormatSpecifiers compares the arguments referenced by the format specifiers in
+// the given values. Arguments are identified by their explicit index or by their
+// position among the specifiers without an explicit index. Like Android, a default
+// value without any valid format argument isn't treated as a format string.
+func compareFormatSpecifiers(defaultValue, translatedValue string) []string {
+	defaultArgs := findFormatArguments(defaultValue)
+	mismatches := make([]string, 0)
+	if len(defaultArgs) == 0 {
+		return mismatches
+	}
+
+	translatedArgs := findFormatArguments(translatedValue)
+	for _, index := range sortedArgumentIndices(defaultArgs) {
+		conversion := defaultArgs[index]
+		if translatedConversion, ok := translatedArgs[index]; !ok {
+			mismatches = append(mismatches, fmt.Sprintf("missing %%%d$%s", index, conversion))
+		} else if translatedConversion != conversion {
+			const mismatchFmt = "%%%d$%s is formatted as %%%d$%s"
+			mismatches = append(mismatches, fmt.Sprintf(mismatchFmt, index, conversion, index, translatedConversion))
+		}
+	}
+
+	for _, index := range sortedArgumentIndices(translatedArgs) {
+		if _, ok := defaultArgs[index]; !ok {
+			mismatches = append(mismatches, fmt.Sprintf("unexpected %%%d$%s", index, translatedArgs[index]))
+		}
+	}
+
+	return mismatches
+}
+
+// findFormatArguments returns the conversions of the format arguments in the given
+// value keyed by their 1-based index. The '%%' and '%n' specifiers are ignored since
+// they don't consume arguments.
+func findFormatArguments(value string) map[int]string {
+	args := make(map[int]string)
+	ordinaryIndex := 0
+	for _, match := range formatSpecifierRegex.FindAllStringSubmatch(value, -1) {
+		conversion := strings.ToLower(match[2])
+		if conversion == "%" || conversion == "n" {
+			continue
+		}
+
+		index, err := strconv.Atoi(match[1])
+		if err != nil { // no explicit index
+			ordinaryIndex++
+			index = ordinaryIndex
+		}
+
+		args[index] = conversion
+	}
+
+	return args
+}
+
+// sortedArgumentIndices returns the keys of the given arguments map in ascending order.
+func sortedArgumentIndices(args map[int]string) []int {
+	indices := make([]int, 0, len(args))
+	for index := range args {
+		indices = append(indices, index)
+	}
+
+	sort.Ints(indices)
+	return indices
+}
+
+// comparePlaceholders compares the '\n' escapes count and the named '{placeholder}'
+// tokens in the given values.
+func comparePlaceholders(defaultValue, translatedValue string) []string {
+	mismatches := make([]string, 0)
+	defaultCount := strings.Count(defaultValue, newlineEscape)
+	translatedCount := strings.Count(translatedValue, newlineEscape)
+	if defaultCount != translatedCount {
+		const mismatchFmt = "expected %d '%s' escapes, found %d"
+		mismatches = append(mismatches, fmt.Sprintf(mismatchFmt, defaultCount, newlineEscape, translatedCount))
+	}
+
+	defaultTokens := toSet(strings.Join(placeholderRegex.FindAllString(defaultValue, -1), " "))
+	translatedTokens := toSet(strings.Join(placeholderRegex.FindAllString(translatedValue, -1), " "))
+	for _, token := range sortedKeys(defaultTokens) {
+		if !translatedTokens[token] {
+			mismatches = append(mismatches, fmt.Sprintf("missing %s", token))
+		}
+	}
+
+	for _, token := range sortedKeys(translatedTokens) {
+		if !defaultTokens[token] {
+			mismatches = append(mismatches, fmt.Sprintf("unexpected %s", token))
+		}
+	}
+
+	return mismatches
+}
+
+// sortedKeys returns the keys of the given set in ascending order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
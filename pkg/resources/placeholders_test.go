@@ -0,0 +1,146 @@
+package resources
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindPlaceholderMismatches(t *testing.T) {
+	tests := []struct {
+		name        string
+		defaultXML  string
+		translation string
+		want        []string
+	}{
+		{
+			name:        "matching",
+			defaultXML:  `<string name="s">%1$s has %2$d items</string>`,
+			translation: `<string name="s">%1$s hat %2$d Elemente</string>`,
+			want:        []string{},
+		},
+		{
+			name:        "reordered",
+			defaultXML:  `<string name="s">%s has %d items</string>`,
+			translation: `<string name="s">%2$d Elemente hat %1$s</string>`,
+			want:        []string{},
+		},
+		{
+			name:        "missing and unexpected",
+			defaultXML:  `<string name="s">%1$s has %2$d items</string>`,
+			translation: `<string name="s">%1$s hat %3$d Elemente</string>`,
+			want:        []string{"missing %2$d", "unexpected %3$d"},
+		},
+		{
+			name:        "different conversion",
+			defaultXML:  `<string name="s">%1$d items</string>`,
+			translation: `<string name="s">%1$s Elemente</string>`,
+			want:        []string{"%1$d is formatted as %1$s"},
+		},
+		{
+			name:        "escapes without arguments",
+			defaultXML:  `<string name="s">100%% done%n</string>`,
+			translation: `<string name="s">fertig</string>`,
+			want:        []string{},
+		},
+		{
+			name:        "percent followed by a space",
+			defaultXML:  `<string name="s">Save 50% on {item}</string>`,
+			translation: `<string name="s">Spare 50 % bei {item}</string>`,
+			want:        []string{},
+		},
+		{
+			name:        "invalid conversion",
+			defaultXML:  `<string name="s">Only %1$d left</string>`,
+			translation: `<string name="s">Nur noch %1$d, 100%ig sicher</string>`,
+			want:        []string{},
+		},
+		{
+			name:        "default without arguments",
+			defaultXML:  `<string name="s">50% off</string>`,
+			translation: `<string name="s">50%o Rabatt</string>`,
+			want:        []string{},
+		},
+		{
+			name:        "not formatted",
+			defaultXML:  `<string name="s" formatted="false">%d%</string>`,
+			translation: `<string name="s" formatted="false">%</string>`,
+			want:        []string{},
+		},
+		{
+			name:        "newline escapes",
+			defaultXML:  `<string name="s">Line 1\nLine 2</string>`,
+			translation: `<string name="s">Zeile 1 Zeile 2</string>`,
+			want:        []string{`expected 1 '\n' escapes, found 0`},
+		},
+		{
+			name:        "named placeholders",
+			defaultXML:  `<string name="s">Hello {name}</string>`,
+			translation: `<string name="s">Hallo {user}</string>`,
+			want:        []string{"missing {name}", "unexpected {user}"},
+		},
+		{
+			name:        "xliff markup",
+			defaultXML:  `<string name="s">Hello <xliff:g id="name" example="Bob">%1$s</xliff:g></string>`,
+			translation: `<string name="s">Hallo <xliff:g id="name">%1$s</xliff:g></string>`,
+			want:        []string{},
+		},
+		{
+			name:        "missing in xliff markup",
+			defaultXML:  `<string name="s"><xliff:g id="count">%1$d</xliff:g> of <xliff:g id="total">%2$d</xliff:g></string>`,
+			translation: `<string name="s"><xliff:g id="count">%1$d</xliff:g> von</string>`,
+			want:        []string{"missing %2$d"},
+		},
+		{
+			name:        "plurals",
+			defaultXML:  `<plurals name="p"><item quantity="one">One item</item><item quantity="other">%d items</item></plurals>`,
+			translation: `<plurals name="p"><item quantity="one">Ein Element</item><item quantity="other">Elemente</item></plurals>`,
+			want:        []string{"missing %1$d"},
+		},
+		{
+			name:        "string array",
+			defaultXML:  `<string-array name="a"><item>%s</item><item>{name}</item></string-array>`,
+			translation: `<string-array name="a"><item>%d</item><item>{name}</item></string-array>`,
+			want:        []string{"item 0: %1$s is formatted as %1$d"},
+		},
+		{
+			name:        "string array item count",
+			defaultXML:  `<string-array name="a"><item>%s</item><item>%s</item></string-array>`,
+			translation: `<string-array name="a"><item>%d</item></string-array>`,
+			want:        []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defaultRes := decodeTestResource(t, test.defaultXML)
+			translatedRes := decodeTestResource(t, test.translation)
+			if got := FindPlaceholderMismatches(defaultRes, translatedRes); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("FindPlaceholderMismatches() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// decodeTestResource decodes the translatable resource in the given XML element. The
+// 'xliff' namespace is declared on a wrapping 'resources' element.
+func decodeTestResource(t *testing.T, element string) Resource {
+	const resourcesFmt = `<resources xmlns:xliff="urn:oasis:names:tc:xliff:document:1.2">%s</resources>`
+	decoder := xml.NewDecoder(strings.NewReader(strings.Replace(resourcesFmt, "%s", element, 1)))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			t.Fatalf("unable to decode %s: %v", element, err)
+		}
+
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local != "resources" {
+			res, ok, err := decodeTranslatableResource(decoder, start)
+			if err != nil || !ok {
+				t.Fatalf("unable to decode %s: %v", element, err)
+			}
+
+			return res
+		}
+	}
+}
@@ -0,0 +1,556 @@
+// Package resources finds and parses the translatable resources in the values XML
+// files of Android projects.
+package resources
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultLocale declares the constant to identify default string resources (resources
+// in 'values' [no suffix] directory)
+const DefaultLocale = "default"
+
+// types of the translatable resources as they appear in the reports.
+const (
+	TypeString      = "string"
+	TypeStringArray = "string-array"
+	TypePlurals     = "plurals"
+)
+
+// toolsNamespace is the namespace of the 'tools:' attributes in Android XML files.
+const toolsNamespace = "http://schemas.android.com/tools"
+
+// Android Lint issue ids in 'tools:ignore' attributes that suppress the findings.
+const (
+	ToolsIgnoreAll                = "all"                // suppresses all the findings
+	ToolsIgnoreMissingTranslation = "MissingTranslation" // suppresses missing translations
+	ToolsIgnoreExtraTranslation   = "ExtraTranslation"   // suppresses stale translations
+)
+
+// xmlTranslatable is a generic struct that can be embedded in other structs
+// to parse values for 'translatable' and 'tools:ignore' attributes
+type xmlTranslatable struct {
+	Translatable string `xml:"translatable,attr"`
+	ToolsIgnore  string `xml:"http://schemas.android.com/tools ignore,attr"`
+}
+
+// IsTranslatable returns false if the value of 'Translatable' attr was set
+// to 'false'. Returns true otherwise.
+func (res *xmlTranslatable) IsTranslatable() bool {
+	return !strings.EqualFold("false", res.Translatable)
+}
+
+// xmlStringResources declares data structure for unmarshalling 'resources' tag in
+// Android values XML files.
+type xmlStringResources struct {
+	xml.Name    `xml:"resources"`
+	ToolsLocale string `xml:"http://schemas.android.com/tools locale,attr"`
+}
+
+// xmlStringResource declares data structure for unmarshalling 'string' tags in Android
+// values XML files.
+type xmlStringResource struct {
+	Name      string `xml:"name,attr"`
+	Value     string `xml:",innerxml"`
+	Formatted string `xml:"formatted,attr"`
+	xmlTranslatable
+}
+
+// xmlStringArrayResource declares data structure for unmarshalling 'string-array' tags
+// in Android values XML files.
+type xmlStringArrayResource struct {
+	Name string `xml:"name,attr"`
+	// since items have only the value, we can re-use xmlStringResource struct
+	Items []xmlStringResource `xml:"item"`
+	xmlTranslatable
+}
+
+// xmlPluralsResource declares data structure for unmarshalling 'plurals' tags in Android
+// values XML files.
+type xmlPluralsResource struct {
+	Name  string           `xml:"name,attr"`
+	Items []xmlPluralsItem `xml:"item"`
+	xmlTranslatable
+}
+
+// xmlPluralsItem declares data structure for unmarshalling 'item' tags of 'plurals'.
+type xmlPluralsItem struct {
+	Quantity string `xml:"quantity,attr"`
+	Value    string `xml:",innerxml"`
+}
+
+// Resource declares the parsed form of a translatable 'string', 'string-array' or
+// 'plurals' resource.
+type Resource struct {
+	Type         string
+	Name         string
+	Value        string            // value of a 'string'
+	Items        []string          // items of a 'string-array'
+	Quantities   map[string]string // items of a 'plurals' keyed by their quantity
+	Raw          RawValues         // inner XML of the values, including their markup
+	Formatted    bool              // false if a 'string' has 'formatted="false"' attribute
+	ToolsIgnore  []string          // issue ids in 'tools:ignore' of the resource or its 'resources' tag
+	File         string            // path of the values file declaring the resource
+	Line         int               // line number of the resource in its file, 0 if unknown
+	LastModified time.Time
+}
+
+// RawValues declares the inner XML of the values of a Resource, e.g. 'Tap <b>here</b>'
+// where the value is 'Tap here'.
+type RawValues struct {
+	Value      string            // inner XML of a 'string'
+	Items      []string          // inner XML of the items of a 'string-array'
+	Quantities map[string]string // inner XML of the items of a 'plurals'
+}
+
+// DisplayValue returns a single line representation of the resource's value. For
+// 'string-array', its items are joined using ", " separator. For 'plurals', the value
+// of its 'other' quantity is used.
+func (res Resource) DisplayValue() string {
+	switch res.Type {
+	case TypeStringArray:
+		return strings.Join(res.Items, ", ")
+	case TypePlurals:
+		return res.Quantities["other"]
+	default:
+		return res.Value
+	}
+}
+
+// IsEmpty returns true if the resource has no value, e.g. a stub written for a missing
+// translation. A 'string-array' or 'plurals' is empty if all of its items are empty.
+func (res Resource) IsEmpty() bool {
+	switch res.Type {
+	case TypeStringArray:
+		for _, item := range res.Items {
+			if item != "" {
+				return false
+			}
+		}
+	case TypePlurals:
+		for _, value := range res.Quantities {
+			if value != "" {
+				return false
+			}
+		}
+	default:
+		return res.Value == ""
+	}
+
+	return true
+}
+
+// IsToolsIgnored returns true if the 'tools:ignore' attribute of the resource, or of its
+// 'resources' tag, contains the given Android Lint issue id or 'all'.
+func (res Resource) IsToolsIgnored(issue string) bool {
+	for _, id := range res.ToolsIgnore {
+		if id == issue || id == ToolsIgnoreAll {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasSameValue checks if the resource has the same type, name and value as 'other'. Their
+// files, lines, 'tools:ignore' attributes and last modified times are ignored.
+func (res Resource) HasSameValue(other Resource) bool {
+	res.File, other.File = "", ""
+	res.Line, other.Line = 0, 0
+	res.ToolsIgnore, other.ToolsIgnore = nil, nil
+	res.LastModified, other.LastModified = time.Time{}, time.Time{}
+	return reflect.DeepEqual(res, other)
+}
+
+// LocaleResources declares the type to map locales => resource_name => Resource
+type LocaleResources map[string]map[string]Resource
+
+// Options declares the options for finding translatable resources.
+type Options struct {
+	// LocaleAliases maps 'values-' suffixes to their canonical locales. See
+	// ResolveLocaleAlias.
+	LocaleAliases map[string]string
+
+	// LastModified, if true, finds the last modified time of each resource using
+	// 'git blame'.
+	LastModified bool
+
+	// Warn, if not nil, is called with the problems that don't prevent parsing. It is
+	// never called concurrently.
+	Warn func(error)
+
+	// Jobs is the number of files to parse concurrently. If it is less than 1, the
+	// number of CPUs is used.
+	Jobs int
+
+	// Cache, if not nil, caches the parsed resources of the files so that only the
+	// files that changed since they were last parsed are parsed again.
+	Cache *ParseCache
+}
+
+// warn calls 'opts.Warn' with 'err' if it is not nil.
+func (opts Options) warn(err error) {
+	if opts.Warn != nil {
+		opts.Warn(err)
+	}
+}
+
+// FindTranslatableResources looks for '<string>', '<string-array>' and '<plurals>' tags
+// with '<resources>' tag as its root in given files. It parses all the tags without
+// 'translatable="false"' attribute. It returns a mapping of locale to their resources
+// where locale is suffix of 'values-'. If no suffix is present, i.e. 'values',
+// DefaultLocale constant is used to identify those values.
+func FindTranslatableResources(files []string, opts Options) (LocaleResources, error) {
+	if opts.Warn != nil {
+		var mutex sync.Mutex
+		warn := opts.Warn
+		opts.Warn = func(err error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			warn(err)
+		}
+	}
+
+	jobs := opts.Jobs
+	if jobs < 1 {
+		jobs = runtime.NumCPU()
+	}
+
+	// parse each file into its own map so that the resources can be merged in the
+	// order of the files, regardless of the order in which they were parsed.
+	fileResources := make([]LocaleResources, len(files))
+	fileErrors := make([]error, len(files))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indices {
+				fileResources[index], fileErrors[index] = parseTranslatableResourcesFile(files[index], opts)
+			}
+		}()
+	}
+
+	for i := range files {
+		indices <- i
+	}
+
+	close(indices)
+	wg.Wait()
+	strResources := make(LocaleResources, 0)
+	for i := range files {
+		if fileErrors[i] != nil {
+			return nil, fileErrors[i]
+		}
+
+		strResources.merge(fileResources[i])
+	}
+
+	return strResources, nil
+}
+
+// parseTranslatableResourcesFile reads the given values file and parses its
+// translatable resources. If 'opts.Cache' is set, it returns the cached resources if
+// the file didn't change since it was last parsed.
+func parseTranslatableResourcesFile(file string, opts Options) (LocaleResources, error) {
+	var info os.FileInfo
+	if opts.Cache != nil {
+		var err error
+		if info, err = os.Stat(file); err != nil {
+			return nil, errors.Wrapf(err, "unable to read file at %s", file)
+		}
+
+		if strResources, ok := opts.Cache.get(file, info); ok {
+			return strResources, nil
+		}
+	}
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read file at %s", file)
+	}
+
+	strResources := make(LocaleResources, 0)
+	if err := parseTranslatableResources(strResources, file, content, opts); err != nil {
+		return nil, err
+	}
+
+	if opts.Cache != nil {
+		opts.Cache.put(file, info, strResources)
+	}
+
+	return strResources, nil
+}
+
+// merge adds the resources in 'other' to 'strResources'. See LocaleResources.add.
+func (strResources LocaleResources) merge(other LocaleResources) {
+	for locale, resources := range other {
+		for _, res := range resources {
+			strResources.add(locale, res)
+		}
+	}
+}
+
+// add adds the given resource under the given locale. If the locale already has a
+// resource with the same name, it is only replaced if the new resource's values
+// directory doesn't have more qualifiers, e.g. a resource in 'values-night' never
+// replaces the one in 'values'.
+func (strResources LocaleResources) add(locale string, res Resource) {
+	if _, ok := strResources[locale]; !ok {
+		strResources[locale] = map[string]Resource{}
+	}
+
+	if existing, ok := strResources[locale][res.Name]; ok && countQualifiers(existing.File) < countQualifiers(res.File) {
+		return
+	}
+
+	strResources[locale][res.Name] = res
+}
+
+// FindTranslatableResourcesAtRevision is similar to FindTranslatableResources but it
+// reads the given files at a git revision, e.g. a commit hash. It skips the files that
+// didn't exist at the revision. The last modified time of resources is never found.
+func FindTranslatableResourcesAtRevision(files []string, revision string, opts Options) (LocaleResources, error) {
+	opts.LastModified = false
+	strResources := make(LocaleResources, 0)
+	for _, file := range files {
+		content, ok, err := getFileAtRevision(file, revision)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+
+		if err := parseTranslatableResources(strResources, file, content, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return strResources, nil
+}
+
+// parseTranslatableResources parses the translatable resources in the given content
+// of a values file and adds them to 'strResources' under the locale of the file. It
+// streams the content using an XML decoder to record the line range of the
+// declaration of each resource. The 'tools:ignore' attribute of the 'resources' tag
+// applies to all the resources in the file.
+func parseTranslatableResources(strResources LocaleResources, file string, content []byte, opts Options) error {
+	const errFmt = "unable to parse XML file at %s"
+	locale := LocaleForValuesFile(file, opts.LocaleAliases)
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	depth := 0
+	var fileToolsIgnore []string
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return errors.Wrapf(err, errFmt, file)
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 1 && token.Name.Local != "resources" {
+				const errFmt = "expected element type <resources> but have <%s> in %s"
+				return fmt.Errorf(errFmt, token.Name.Local, file)
+			}
+
+			if depth == 1 {
+				for _, attr := range token.Attr {
+					if attr.Name.Space == toolsNamespace && attr.Name.Local == "ignore" {
+						fileToolsIgnore = parseToolsIgnore(attr.Value)
+					}
+				}
+			}
+
+			if depth != 2 {
+				continue
+			}
+
+			res, ok, err := decodeTranslatableResource(decoder, token)
+			if err != nil {
+				return errors.Wrapf(err, errFmt, file)
+			}
+
+			// the decoder consumes the end element of the resource.
+			depth--
+			if !ok {
+				continue
+			}
+
+			start := 1 + bytes.Count(content[:offset], []byte("\n"))
+			end := 1 + bytes.Count(content[:decoder.InputOffset()], []byte("\n"))
+			res.File = file
+			res.Line = start
+			res.ToolsIgnore = append(res.ToolsIgnore, fileToolsIgnore...)
+			res.LastModified = findLastModifiedTime(file, opts, start, end-start+1)
+			strResources.add(locale, res)
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	if depth > 0 {
+		return errors.Wrapf(io.ErrUnexpectedEOF, errFmt, file)
+	}
+
+	return nil
+}
+
+// decodeTranslatableResource decodes the element starting at 'start' using 'decoder'.
+// It returns false if the element isn't a translatable resource.
+func decodeTranslatableResource(decoder *xml.Decoder, start xml.StartElement) (Resource, bool, error) {
+	switch start.Name.Local {
+	case TypeString:
+		str := xmlStringResource{}
+		if err := decoder.DecodeElement(&str, &start); err != nil || !str.IsTranslatable() {
+			return Resource{}, false, err
+		}
+
+		return Resource{
+			Type:        TypeString,
+			Name:        str.Name,
+			Value:       innerText(str.Value),
+			Raw:         RawValues{Value: strings.TrimSpace(str.Value)},
+			Formatted:   !strings.EqualFold("false", str.Formatted),
+			ToolsIgnore: parseToolsIgnore(str.ToolsIgnore),
+		}, true, nil
+	case TypeStringArray:
+		strArr := xmlStringArrayResource{}
+		if err := decoder.DecodeElement(&strArr, &start); err != nil || !strArr.IsTranslatable() {
+			return Resource{}, false, err
+		}
+
+		items := make([]string, 0, len(strArr.Items))
+		rawItems := make([]string, 0, len(strArr.Items))
+		for _, strArrItem := range strArr.Items {
+			items = append(items, innerText(strArrItem.Value))
+			rawItems = append(rawItems, strings.TrimSpace(strArrItem.Value))
+		}
+
+		return Resource{
+			Type:        TypeStringArray,
+			Name:        strArr.Name,
+			Items:       items,
+			Raw:         RawValues{Items: rawItems},
+			Formatted:   true,
+			ToolsIgnore: parseToolsIgnore(strArr.ToolsIgnore),
+		}, true, nil
+	case TypePlurals:
+		plurals := xmlPluralsResource{}
+		if err := decoder.DecodeElement(&plurals, &start); err != nil || !plurals.IsTranslatable() {
+			return Resource{}, false, err
+		}
+
+		quantities := make(map[string]string, len(plurals.Items))
+		rawQuantities := make(map[string]string, len(plurals.Items))
+		for _, pluralsItem := range plurals.Items {
+			quantity := strings.TrimSpace(pluralsItem.Quantity)
+			quantities[quantity] = innerText(pluralsItem.Value)
+			rawQuantities[quantity] = strings.TrimSpace(pluralsItem.Value)
+		}
+
+		return Resource{
+			Type:        TypePlurals,
+			Name:        plurals.Name,
+			Quantities:  quantities,
+			Raw:         RawValues{Quantities: rawQuantities},
+			Formatted:   true,
+			ToolsIgnore: parseToolsIgnore(plurals.ToolsIgnore),
+		}, true, nil
+	default:
+		return Resource{}, false, decoder.Skip()
+	}
+}
+
+// innerText returns the character data in the given inner XML of an element, including
+// the character data of its nested elements, e.g. the placeholders in '<xliff:g>' tags,
+// with the leading and trailing white space removed.
+func innerText(innerXML string) string {
+	var text strings.Builder
+	decoder := xml.NewDecoder(strings.NewReader(innerXML))
+	for {
+		token, err := decoder.Token()
+		if err != nil { // the element was already decoded, so it can only be io.EOF
+			break
+		}
+
+		if charData, ok := token.(xml.CharData); ok {
+			text.Write(charData)
+		}
+	}
+
+	return strings.TrimSpace(text.String())
+}
+
+// parseToolsIgnore returns the comma-separated issue ids in the value of a
+// 'tools:ignore' attribute.
+func parseToolsIgnore(value string) []string {
+	ids := make([]string, 0)
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// FindDefaultLanguage returns the language declared using 'tools:locale' attribute on
+// the 'resources' tag of default values files. If none of the files declare it, it
+// returns 'en', the language assumed by Android Developer Tools.
+func FindDefaultLanguage(valuesFiles []string) (string, error) {
+	for _, file := range valuesFiles {
+		if LocaleForValuesFile(file, nil) != DefaultLocale {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to read file at %s", file)
+		}
+
+		resources := &xmlStringResources{}
+		if err = xml.Unmarshal(content, resources); err != nil {
+			return "", errors.Wrapf(err, "unable to parse XML file at %s", file)
+		}
+
+		if resources.ToolsLocale != "" {
+			return strings.SplitN(LocaleToLanguageTag(resources.ToolsLocale), "-", 2)[0], nil
+		}
+	}
+
+	return "en", nil
+}
+
+// findLastModifiedTime returns the last modified time of the given line range of the
+// file. If it fails to find the last modified time, it warns and returns the current
+// time. If 'opts.LastModified' is false, it returns the zero time.
+func findLastModifiedTime(file string, opts Options, start int, count int) time.Time {
+	if !opts.LastModified {
+		return time.Time{}
+	}
+
+	modified, err := getLastModifiedTime(file, start, count)
+	if err != nil {
+		opts.warn(err)
+		return time.Now()
+	}
+
+	return modified
+}